@@ -0,0 +1,191 @@
+package godiator
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/baranius/godiator/core/contexts"
+	"github.com/baranius/godiator/core/interfaces"
+	"github.com/baranius/godiator/pipeline"
+)
+
+const (
+	defaultBatchWindow  = 10 * time.Millisecond
+	defaultMaxBatchSize = 100
+)
+
+// BatchOption configures how concurrent requests are coalesced by a batch handler.
+type BatchOption func(*batchOptions)
+
+type batchOptions struct {
+	window       time.Duration
+	maxBatchSize int
+}
+
+// WithBatchWindow sets how long the first request of a batch waits for other requests
+// before the batch is dispatched. Defaults to 10ms.
+func WithBatchWindow(window time.Duration) BatchOption {
+	return func(o *batchOptions) {
+		if window > 0 {
+			o.window = window
+		}
+	}
+}
+
+// WithMaxBatchSize sets the maximum number of requests in a batch. A batch is dispatched
+// as soon as it is full, without waiting for the window to expire. Defaults to 100.
+func WithMaxBatchSize(size int) BatchOption {
+	return func(o *batchOptions) {
+		if size > 0 {
+			o.maxBatchSize = size
+		}
+	}
+}
+
+// batchKey groups the requests sent by the same principal within the same transaction.
+type batchKey struct {
+	principal any
+	tx        any
+}
+
+// batchKeyOf returns the key of the batch the request joins. Requests whose principal or
+// transaction cannot be compared are not batched with other requests.
+func batchKeyOf(params []any) (batchKey, bool) {
+	var key batchKey
+	if principal, ok := contexts.PrincipalFrom(params...); ok {
+		key.principal = principal
+	}
+	if tx, ok := pipeline.TxFromContext(contexts.From(params...)); ok {
+		key.tx = tx
+	}
+	for _, value := range []any{key.principal, key.tx} {
+		if value != nil && !reflect.TypeOf(value).Comparable() {
+			return key, false
+		}
+	}
+	return key, true
+}
+
+// batch holds the requests collected during a single window.
+type batch[TRequest any, TResponse any] struct {
+	key       batchKey
+	requests  []TRequest
+	params    []any
+	responses []TResponse
+	err       error
+	timer     *time.Timer
+	done      chan struct{}
+	// workID tracks the batch from its first request until it has been processed
+	workID uint64
+}
+
+// batchHandler adapts a BatchHandler to the Handler interface so that it can sit
+// behind Send like any other handler.
+type batchHandler[TRequest any, TResponse any] struct {
+	handler interfaces.BatchHandler[TRequest, TResponse]
+	options batchOptions
+
+	mu      sync.Mutex
+	pending map[batchKey]*batch[TRequest, TResponse]
+}
+
+func newBatchHandler[TRequest any, TResponse any](handler interfaces.BatchHandler[TRequest, TResponse], opts ...BatchOption) *batchHandler[TRequest, TResponse] {
+	options := batchOptions{window: defaultBatchWindow, maxBatchSize: defaultMaxBatchSize}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &batchHandler[TRequest, TResponse]{handler: handler, options: options, pending: make(map[batchKey]*batch[TRequest, TResponse])}
+}
+
+// Handle adds the request to the pending batch of its principal and transaction, and blocks
+// until the batch has been processed, or until the context found in params is done. The batch
+// handler receives the params of the first request in the batch, with its context detached
+// from cancellation so that the first caller giving up does not fail every other request in
+// the batch. The other params of the other requests, including their contexts, do not reach
+// the batch handler.
+func (b *batchHandler[TRequest, TResponse]) Handle(request TRequest, params ...any) (TResponse, error) {
+	key, batched := batchKeyOf(params)
+
+	b.mu.Lock()
+	current := b.pending[key]
+	if current == nil || !batched {
+		current = &batch[TRequest, TResponse]{
+			key:    key,
+			params: contexts.Detach(params),
+			done:   make(chan struct{}),
+			workID: inFlight.add(AbandonedWork{Kind: "batch", RequestType: reflect.TypeFor[TRequest]().String()}),
+		}
+		if batched {
+			current.timer = time.AfterFunc(b.options.window, func() { b.flush(current) })
+			b.pending[key] = current
+		}
+	}
+	index := len(current.requests)
+	current.requests = append(current.requests, request)
+
+	if !batched {
+		b.mu.Unlock()
+		go b.execute(current)
+	} else if len(current.requests) >= b.options.maxBatchSize {
+		current.timer.Stop()
+		delete(b.pending, key)
+		b.mu.Unlock()
+		go b.execute(current)
+	} else {
+		b.mu.Unlock()
+	}
+
+	var response TResponse
+	ctx := ContextFrom(params...)
+	select {
+	case <-current.done:
+	case <-ctx.Done():
+		return response, ctx.Err()
+	}
+	if current.err != nil {
+		return response, current.err
+	}
+	return current.responses[index], nil
+}
+
+// flush dispatches the batch when its window expires, unless it was already dispatched
+// because it reached the maximum size.
+func (b *batchHandler[TRequest, TResponse]) flush(current *batch[TRequest, TResponse]) {
+	b.mu.Lock()
+	if b.pending[current.key] != current {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.pending, current.key)
+	b.mu.Unlock()
+
+	b.execute(current)
+}
+
+// execute runs the batch handler and wakes up every caller waiting on the batch, then stops
+// tracking the batch. A panic is converted into an error because it would otherwise surface on
+// whichever goroutine happened to dispatch the batch.
+func (b *batchHandler[TRequest, TResponse]) execute(current *batch[TRequest, TResponse]) {
+	defer func() {
+		if r := recover(); r != nil {
+			current.err = fmt.Errorf("batch handler panicked: %v", r)
+		}
+		close(current.done)
+		inFlight.end(current.workID)
+	}()
+
+	responses, err := b.handler.Handle(current.requests, current.params...)
+	if err != nil {
+		current.err = err
+		return
+	}
+	if len(responses) != len(current.requests) {
+		var request TRequest
+		current.err = fmt.Errorf(`batch handler for "%s" returned %d responses for %d requests`,
+			reflect.TypeOf(request).String(), len(responses), len(current.requests))
+		return
+	}
+	current.responses = responses
+}
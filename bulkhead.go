@@ -0,0 +1,148 @@
+package godiator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+// ErrBulkheadFull is matched by every *BulkheadFullError with errors.Is.
+var ErrBulkheadFull = errors.New("bulkhead is full")
+
+// BulkheadFullError is returned by Send when a handler registered WithMaxConcurrency
+// is running at capacity and its queue is full.
+type BulkheadFullError struct {
+	RequestType    string
+	MaxConcurrency int
+	MaxQueue       int
+}
+
+// Error implements the error interface.
+func (e *BulkheadFullError) Error() string {
+	return fmt.Sprintf(`handler for "%s" is at capacity (%d in flight, %d queued)`, e.RequestType, e.MaxConcurrency, e.MaxQueue)
+}
+
+// Is reports whether the target is ErrBulkheadFull.
+func (e *BulkheadFullError) Is(target error) bool {
+	return target == ErrBulkheadFull
+}
+
+// BulkheadStats describes the current load of a handler registered WithMaxConcurrency.
+type BulkheadStats struct {
+	InFlight       int
+	Queued         int
+	MaxConcurrency int
+	MaxQueue       int
+}
+
+var (
+	bulkheadsMu sync.RWMutex
+	bulkheads   = make(map[reflect.Type]bulkheadStatter)
+)
+
+type bulkheadStatter interface {
+	stats() BulkheadStats
+}
+
+// bulkheadHandler caps the number of concurrent executions of a handler.
+type bulkheadHandler[TRequest any, TResponse any] struct {
+	handler  interfaces.Handler[TRequest, TResponse]
+	slots    chan struct{}
+	maxQueue int
+	inFlight atomic.Int64
+	queued   atomic.Int64
+}
+
+func newBulkheadHandler[TRequest any, TResponse any](handler interfaces.Handler[TRequest, TResponse], maxConcurrency int, maxQueue int) *bulkheadHandler[TRequest, TResponse] {
+	return &bulkheadHandler[TRequest, TResponse]{
+		handler:  handler,
+		slots:    make(chan struct{}, maxConcurrency),
+		maxQueue: maxQueue,
+	}
+}
+
+// Handle runs the handler if a slot is free, otherwise waits in the queue for one.
+// Waiting stops when the context found in params is done.
+func (b *bulkheadHandler[TRequest, TResponse]) Handle(request TRequest, params ...any) (TResponse, error) {
+	var response TResponse
+
+	select {
+	case b.slots <- struct{}{}:
+	default:
+		if b.queued.Add(1) > int64(b.maxQueue) {
+			b.queued.Add(-1)
+			return response, &BulkheadFullError{
+				RequestType:    reflect.TypeOf(request).String(),
+				MaxConcurrency: cap(b.slots),
+				MaxQueue:       b.maxQueue,
+			}
+		}
+		ctx := ContextFrom(params...)
+		select {
+		case b.slots <- struct{}{}:
+			b.queued.Add(-1)
+		case <-ctx.Done():
+			b.queued.Add(-1)
+			return response, ctx.Err()
+		}
+	}
+
+	b.inFlight.Add(1)
+	defer func() {
+		b.inFlight.Add(-1)
+		<-b.slots
+	}()
+	return b.handler.Handle(request, params...)
+}
+
+func (b *bulkheadHandler[TRequest, TResponse]) stats() BulkheadStats {
+	return BulkheadStats{
+		InFlight:       int(b.inFlight.Load()),
+		Queued:         int(b.queued.Load()),
+		MaxConcurrency: cap(b.slots),
+		MaxQueue:       b.maxQueue,
+	}
+}
+
+// HandlerStats returns the current load of the handler registered for the request type
+// WithMaxConcurrency.
+//
+// Type parameters:
+//   - TRequest: The request type of the handler
+//
+// Returns:
+//   - BulkheadStats: The current load of the handler
+//   - bool: Indicates whether the handler was registered WithMaxConcurrency
+//
+// Example:
+//
+//	stats, ok := godiator.HandlerStats[GenerateReportRequest]()
+//	log.Printf("%d reports in flight, %d queued", stats.InFlight, stats.Queued)
+func HandlerStats[TRequest any]() (BulkheadStats, bool) {
+	bulkheadsMu.RLock()
+	defer bulkheadsMu.RUnlock()
+
+	var request TRequest
+	bulkhead, ok := bulkheads[reflect.TypeOf(request)]
+	if !ok {
+		return BulkheadStats{}, false
+	}
+	return bulkhead.stats(), true
+}
+
+// setBulkhead records the bulkhead of a request type, or forgets it when bulkhead is nil.
+func setBulkhead[TRequest any](bulkhead bulkheadStatter) {
+	bulkheadsMu.Lock()
+	defer bulkheadsMu.Unlock()
+
+	var request TRequest
+	if bulkhead == nil {
+		delete(bulkheads, reflect.TypeOf(request))
+	} else {
+		bulkheads[reflect.TypeOf(request)] = bulkhead
+	}
+}
@@ -0,0 +1,54 @@
+package godiator
+
+import (
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+// CacheInvalidation lists the cache keys and tags to remove when an event is published.
+type CacheInvalidation struct {
+	// Keys are removed with Cache.Delete. Entries stored by the caching pipeline are keyed
+	// by pipeline.CacheEntryKey, which prefixes the cache key with the request type.
+	Keys []string
+	// Tags are removed with Cache.DeleteTags.
+	Tags []string
+}
+
+// InvalidateCacheOn declares that publishing an event of type TEvent invalidates entries
+// of the cache. The invalidation runs synchronously inside Publish, before subscribers are
+// started, so queries sent after Publish returns never observe the stale entries. Queries
+// still running when the event is published may store a stale response afterwards.
+//
+// Type parameters:
+//   - TEvent: The event type that triggers the invalidation
+//
+// Parameters:
+//   - cache: The cache to invalidate
+//   - invalidation: Returns the keys and tags to remove for a published event
+//
+// Returns:
+//   - func() bool: Removes the invalidation, reporting whether it was still registered
+//
+// Example:
+//
+//	stop := godiator.InvalidateCacheOn(cache, func(e ProductUpdatedEvent) godiator.CacheInvalidation {
+//	    return godiator.CacheInvalidation{
+//	        Keys: []string{pipeline.CacheEntryKey(GetProductRequest{SKU: e.SKU})},
+//	        Tags: []string{"catalog"},
+//	    }
+//	})
+//	defer stop()
+func InvalidateCacheOn[TEvent any](cache interfaces.Cache, invalidation func(event TEvent) CacheInvalidation) func() bool {
+	id := core.AddPublishHook(func(event TEvent) {
+		result := invalidation(event)
+		if len(result.Keys) > 0 {
+			cache.Delete(result.Keys...)
+		}
+		if len(result.Tags) > 0 {
+			cache.DeleteTags(result.Tags...)
+		}
+	})
+	return func() bool {
+		return core.RemovePublishHookByID(id)
+	}
+}
@@ -0,0 +1,35 @@
+package godiator
+
+import (
+	"context"
+
+	"github.com/baranius/godiator/core/contexts"
+)
+
+// ContextFrom returns the first context.Context found in params, or context.Background()
+// if there is none. Handlers, subscribers and pipelines use it to retrieve the context
+// passed to Send or Publish, including deadlines and values added by pipelines.
+//
+// Example:
+//
+//	func (h *GetUserHandler) Handle(req GetUserRequest, params ...any) (GetUserResponse, error) {
+//	    ctx := godiator.ContextFrom(params...)
+//	    return h.repository.Get(ctx, req.ID)
+//	}
+//	godiator.Send[GetUserRequest, GetUserResponse](GetUserRequest{ID: 1}, ctx)
+func ContextFrom(params ...any) context.Context {
+	return contexts.From(params...)
+}
+
+// WithContext returns a copy of params in which the first context.Context is replaced by ctx.
+// If params holds no context, ctx is prepended. Pipelines use it to pass a derived context
+// down the chain.
+//
+// Example:
+//
+//	ctx, cancel := context.WithCancel(godiator.ContextFrom(params...))
+//	defer cancel()
+//	return p.Next().Handle(request, godiator.WithContext(ctx, params...)...)
+func WithContext(ctx context.Context, params ...any) []any {
+	return contexts.With(ctx, params...)
+}
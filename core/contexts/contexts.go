@@ -0,0 +1,95 @@
+// Package contexts finds and replaces the context.Context passed in the params of Send and
+// Publish. It is shared by the mediator and the pipeline package, which cannot depend on each
+// other both ways; applications use the equivalent functions of the godiator package.
+package contexts
+
+import (
+	"context"
+
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+type principalKey struct{}
+
+// From returns the first context.Context found in params, or context.Background() if there
+// is none.
+//
+// Parameters:
+//   - params: The params passed to Send or Publish
+//
+// Returns:
+//   - context.Context: The context of the params
+func From(params ...any) context.Context {
+	for _, param := range params {
+		if ctx, ok := param.(context.Context); ok && ctx != nil {
+			return ctx
+		}
+	}
+	return context.Background()
+}
+
+// With returns a copy of params in which the first context.Context is replaced by ctx.
+// If params holds no context, ctx is prepended.
+//
+// Parameters:
+//   - ctx: The context to pass on
+//   - params: The params passed to Send or Publish
+//
+// Returns:
+//   - []any: The params holding ctx
+func With(ctx context.Context, params ...any) []any {
+	result := make([]any, len(params), len(params)+1)
+	copy(result, params)
+	for i, param := range result {
+		if _, ok := param.(context.Context); ok {
+			result[i] = ctx
+			return result
+		}
+	}
+	return append([]any{ctx}, result...)
+}
+
+// Detach returns a copy of params in which the context, if any, is no longer cancelled along
+// with the caller's. Work shared by several callers runs with it so that one caller giving up
+// does not fail the others.
+//
+// Parameters:
+//   - params: The params passed to Send or Publish
+//
+// Returns:
+//   - []any: The params holding the detached context, or params if they hold no context
+func Detach(params []any) []any {
+	for _, param := range params {
+		if ctx, ok := param.(context.Context); ok && ctx != nil {
+			return With(context.WithoutCancel(ctx), params...)
+		}
+	}
+	return params
+}
+
+// WithPrincipal returns a copy of ctx carrying the principal.
+//
+// Parameters:
+//   - ctx: The parent context
+//   - principal: The principal sending the request
+//
+// Returns:
+//   - context.Context: The context carrying the principal
+func WithPrincipal(ctx context.Context, principal interfaces.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal of a request, taken from the context found in params.
+// Other params are never taken for the principal, even if they implement Principal, so that
+// a value passed for another purpose cannot act as the identity of the request.
+//
+// Parameters:
+//   - params: The params passed to Send
+//
+// Returns:
+//   - interfaces.Principal: The principal
+//   - bool: Indicates whether a principal was found
+func PrincipalFrom(params ...any) (interfaces.Principal, bool) {
+	principal, ok := From(params...).Value(principalKey{}).(interfaces.Principal)
+	return principal, ok
+}
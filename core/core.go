@@ -7,7 +7,11 @@
 package core
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"reflect"
+	"slices"
 	"sync"
 
 	"github.com/baranius/godiator/core/interfaces"
@@ -17,7 +21,22 @@ var (
 	mu                 sync.RWMutex
 	messageHandlers    = make(map[reflect.Type]interfaces.Handler[any, any])
 	messageSubscribers = make(map[reflect.Type][]interfaces.Subscriber[any])
-	messagePipelines   = make([]interfaces.Pipeline, 0)
+	messagePipelines   = make([]PipelineEntry, 0)
+	publishHooks       = make(map[reflect.Type][]publishHookEntry)
+	nextPublishHookID  uint64
+	publishers         = make(map[reflect.Type]any)
+	messageValidators  = make(map[reflect.Type][]interfaces.Validator[any])
+	messagePolicies    = make(map[reflect.Type][]interfaces.Policy[any])
+	preProcessors      = make(map[reflect.Type][]any)
+	postProcessors     = make(map[reflect.Type][]any)
+	exceptionHandlers  = make(map[reflect.Type][]any)
+	metrics            interfaces.Metrics
+	tracer             interfaces.Tracer
+	observers          = make([]observerEntry, 0)
+	nextObserverID     uint64
+
+	notificationPipelines      = make([]interfaces.NotificationPipeline, 0)
+	eventNotificationPipelines = make(map[reflect.Type][]interfaces.NotificationPipeline)
 )
 
 // Wrapper for safe interfaces conversion
@@ -32,12 +51,48 @@ func (w *handlerWrapper[TRequest, TResponse]) Handle(request any, params ...any)
 // Wrapper for safe interfaces conversion
 type subscriberWrapper[TRequest any] struct {
 	subscriber interfaces.Subscriber[TRequest]
+	priority   int
 }
 
 func (w *subscriberWrapper[TRequest]) Handle(request any, params ...any) {
 	w.subscriber.Handle(request.(TRequest), params...)
 }
 
+// HandleFallible runs the wrapped subscriber and returns the error it failed with: the error
+// it reports with HandleFallible when it decorates a fallible subscriber, or nil.
+func (w *subscriberWrapper[TRequest]) HandleFallible(request any, params ...any) error {
+	if fallible, ok := w.subscriber.(interface {
+		HandleFallible(TRequest, ...any) error
+	}); ok {
+		return fallible.HandleFallible(request.(TRequest), params...)
+	}
+	w.subscriber.Handle(request.(TRequest), params...)
+	return nil
+}
+
+// Name returns the name of the wrapped subscriber: the name it reports with SubscriberName
+// when it decorates another subscriber, or its type.
+func (w *subscriberWrapper[TRequest]) Name() string {
+	if named, ok := w.subscriber.(interface{ SubscriberName() string }); ok {
+		return named.SubscriberName()
+	}
+	return fmt.Sprintf("%T", w.subscriber)
+}
+
+// Accepts reports whether the wrapped subscriber receives the event: false when it filters
+// events with an Accepts method rejecting this one, true otherwise.
+func (w *subscriberWrapper[TRequest]) Accepts(request TRequest) bool {
+	if filtered, ok := w.subscriber.(interface{ Accepts(TRequest) bool }); ok {
+		return filtered.Accepts(request)
+	}
+	return true
+}
+
+// Priority returns the priority the subscriber was registered with.
+func (w *subscriberWrapper[TRequest]) Priority() int {
+	return w.priority
+}
+
 // AddHandler registers a handler for a specific request and response type pair.
 // Only one handler can be registered per request type. If a handler already exists
 // for the request type, it will be replaced.
@@ -92,6 +147,151 @@ func RemoveHandler[TRequest any]() {
 	delete(messageHandlers, reflect.TypeOf(request))
 }
 
+// Wrapper for safe interfaces conversion
+type validatorWrapper[TRequest any] struct {
+	validator interfaces.Validator[TRequest]
+}
+
+func (w *validatorWrapper[TRequest]) Validate(request any) error {
+	return w.validator.Validate(request.(TRequest))
+}
+
+// AddPreProcessor registers a pre-processor for a specific request type.
+// Multiple pre-processors can be registered; they are executed in registration order.
+//
+// Type parameters:
+//   - TRequest: The request type that the pre-processor will process
+//
+// Parameters:
+//   - processor: The pre-processor to register
+func AddPreProcessor[TRequest any](processor interfaces.PreProcessor[TRequest]) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var request TRequest
+	requestType := reflect.TypeOf(request)
+	preProcessors[requestType] = append(preProcessors[requestType], processor)
+}
+
+// GetPreProcessors returns the pre-processors registered for the specified request type.
+//
+// Returns:
+//   - []interfaces.PreProcessor[TRequest]: The list of pre-processors
+func GetPreProcessors[TRequest any]() []interfaces.PreProcessor[TRequest] {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var request TRequest
+	var result []interfaces.PreProcessor[TRequest]
+	for _, processor := range preProcessors[reflect.TypeOf(request)] {
+		if processor, ok := processor.(interfaces.PreProcessor[TRequest]); ok {
+			result = append(result, processor)
+		}
+	}
+	return result
+}
+
+// AddPostProcessor registers a post-processor for a specific request and response type pair.
+// Multiple post-processors can be registered; they are executed in registration order.
+//
+// Type parameters:
+//   - TRequest: The request type that the post-processor will process
+//   - TResponse: The response type returned by the handler
+//
+// Parameters:
+//   - processor: The post-processor to register
+func AddPostProcessor[TRequest any, TResponse any](processor interfaces.PostProcessor[TRequest, TResponse]) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var request TRequest
+	requestType := reflect.TypeOf(request)
+	postProcessors[requestType] = append(postProcessors[requestType], processor)
+}
+
+// GetPostProcessors returns the post-processors registered for the specified request and response types.
+//
+// Returns:
+//   - []interfaces.PostProcessor[TRequest, TResponse]: The list of post-processors
+func GetPostProcessors[TRequest any, TResponse any]() []interfaces.PostProcessor[TRequest, TResponse] {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var request TRequest
+	var result []interfaces.PostProcessor[TRequest, TResponse]
+	for _, processor := range postProcessors[reflect.TypeOf(request)] {
+		if processor, ok := processor.(interfaces.PostProcessor[TRequest, TResponse]); ok {
+			result = append(result, processor)
+		}
+	}
+	return result
+}
+
+// ExceptionHandlerFunc is an exception handler adapted to accept any error.
+// It reports false when the error does not match the handler's error type.
+type ExceptionHandlerFunc[TRequest any, TResponse any] func(request TRequest, err error, params ...any) (TResponse, bool)
+
+// AddExceptionHandler registers an exception handler for a specific request, response and error type.
+// Multiple exception handlers can be registered; the first one handling an error wins.
+//
+// Type parameters:
+//   - TRequest: The request type that the exception handler will process
+//   - TResponse: The response type returned by the handler
+//   - TErr: The error type that the exception handler will handle
+//
+// Parameters:
+//   - handler: The exception handler to register
+func AddExceptionHandler[TRequest any, TResponse any, TErr error](handler interfaces.ExceptionHandler[TRequest, TResponse, TErr]) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var request TRequest
+	requestType := reflect.TypeOf(request)
+	exceptionHandlers[requestType] = append(exceptionHandlers[requestType], ExceptionHandlerFunc[TRequest, TResponse](
+		func(request TRequest, err error, params ...any) (TResponse, bool) {
+			var target TErr
+			if !errors.As(err, &target) {
+				var response TResponse
+				return response, false
+			}
+			return handler.Handle(request, target, params...)
+		}))
+}
+
+// GetExceptionHandlers returns the exception handlers registered for the specified request and response types.
+//
+// Returns:
+//   - []ExceptionHandlerFunc[TRequest, TResponse]: The list of exception handlers
+func GetExceptionHandlers[TRequest any, TResponse any]() []ExceptionHandlerFunc[TRequest, TResponse] {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var request TRequest
+	var result []ExceptionHandlerFunc[TRequest, TResponse]
+	for _, handler := range exceptionHandlers[reflect.TypeOf(request)] {
+		if handler, ok := handler.(ExceptionHandlerFunc[TRequest, TResponse]); ok {
+			result = append(result, handler)
+		}
+	}
+	return result
+}
+
+// RemoveProcessors unregisters all pre-processors, post-processors and exception handlers
+// for the specified request type.
+//
+// Type parameters:
+//   - TRequest: The request type whose processors should be removed
+func RemoveProcessors[TRequest any]() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var request TRequest
+	requestType := reflect.TypeOf(request)
+	delete(preProcessors, requestType)
+	delete(postProcessors, requestType)
+	delete(exceptionHandlers, requestType)
+}
+
 // AddSubscriber registers one or more subscribers for a specific request type.
 // Subscribers are executed asynchronously when Publish is called.
 //
@@ -101,22 +301,42 @@ func RemoveHandler[TRequest any]() {
 // Parameters:
 //   - subscribers: The subscribers to register
 func AddSubscriber[TRequest any](subscribers ...interfaces.Subscriber[TRequest]) {
+	AddSubscriberWithPriority(0, subscribers...)
+}
+
+// AddSubscriberWithPriority registers one or more subscribers for a specific request type with
+// a priority. Subscribers are kept in descending order of priority, and in order of registration
+// among subscribers of the same priority.
+//
+// Type parameters:
+//   - TRequest: The request type that the subscribers will process
+//
+// Parameters:
+//   - priority: The priority of the subscribers; higher priorities come first
+//   - subscribers: The subscribers to register
+func AddSubscriberWithPriority[TRequest any](priority int, subscribers ...interfaces.Subscriber[TRequest]) {
 	mu.Lock()
 	defer mu.Unlock()
 
 	var request TRequest
 	requestType := reflect.TypeOf(request)
 	for _, s := range subscribers {
-		wrapper := &subscriberWrapper[TRequest]{subscriber: s}
-		if existing := messageSubscribers[requestType]; existing != nil {
-			messageSubscribers[requestType] = append(existing, wrapper)
-		} else {
-			messageSubscribers[requestType] = []interfaces.Subscriber[any]{wrapper}
+		wrapper := &subscriberWrapper[TRequest]{subscriber: s, priority: priority}
+		existing := messageSubscribers[requestType]
+		// Insert after every subscriber of the same or a higher priority
+		index := len(existing)
+		for i, sub := range existing {
+			if prioritized, ok := sub.(interface{ Priority() int }); ok && prioritized.Priority() < priority {
+				index = i
+				break
+			}
 		}
+		messageSubscribers[requestType] = slices.Insert(existing, index, interfaces.Subscriber[any](wrapper))
 	}
 }
 
-// GetSubscribers returns a list of subscriber wrappers for the specified request type.
+// GetSubscribers returns a list of subscriber wrappers for the specified request type, in
+// descending order of priority.
 //
 // Returns:
 //   - []subscriberWrapper[TRequest]: The list of subscriber wrappers.
@@ -150,20 +370,315 @@ func RemoveSubscriber[TRequest any]() {
 	delete(messageSubscribers, reflect.TypeOf(request))
 }
 
+// AddValidator registers a validator for a specific request type.
+// Multiple validators can be registered for the same request type.
+//
+// Type parameters:
+//   - TRequest: The request type that the validator will check
+//
+// Parameters:
+//   - validator: The validator to register
+func AddValidator[TRequest any](validator interfaces.Validator[TRequest]) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var request TRequest
+	requestType := reflect.TypeOf(request)
+	messageValidators[requestType] = append(messageValidators[requestType], &validatorWrapper[TRequest]{validator})
+}
+
+// GetValidators returns the validators registered for the dynamic type of the request.
+//
+// Parameters:
+//   - request: The request to be validated
+//
+// Returns:
+//   - []interfaces.Validator[any]: The list of validators
+func GetValidators(request any) []interfaces.Validator[any] {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return messageValidators[reflect.TypeOf(request)]
+}
+
+// RemoveValidators unregisters all validators for the specified request type.
+//
+// Type parameters:
+//   - TRequest: The request type whose validators should be removed
+func RemoveValidators[TRequest any]() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var request TRequest
+	delete(messageValidators, reflect.TypeOf(request))
+}
+
+// AddPolicy registers an authorization policy for a specific request type.
+// Multiple policies can be registered for the same request type.
+//
+// Type parameters:
+//   - TRequest: The request type that the policy will authorize
+//
+// Parameters:
+//   - policy: The policy to register
+func AddPolicy[TRequest any](policy interfaces.Policy[TRequest]) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var request TRequest
+	requestType := reflect.TypeOf(request)
+	messagePolicies[requestType] = append(messagePolicies[requestType], func(ctx context.Context, principal interfaces.Principal, request any) error {
+		return policy(ctx, principal, request.(TRequest))
+	})
+}
+
+// GetPolicies returns the policies registered for the dynamic type of the request.
+//
+// Parameters:
+//   - request: The request to be authorized
+//
+// Returns:
+//   - []interfaces.Policy[any]: The list of policies
+func GetPolicies(request any) []interfaces.Policy[any] {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return messagePolicies[reflect.TypeOf(request)]
+}
+
+// RemovePolicies unregisters all policies for the specified request type.
+//
+// Type parameters:
+//   - TRequest: The request type whose policies should be removed
+func RemovePolicies[TRequest any]() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var request TRequest
+	delete(messagePolicies, reflect.TypeOf(request))
+}
+
+// publishHookEntry is a registered publish hook with the ID identifying its registration.
+type publishHookEntry struct {
+	id   uint64
+	hook func(request any)
+}
+
+// AddPublishHook registers a hook that is executed synchronously when a request of the
+// specified type is published, before any subscriber is started.
+//
+// Type parameters:
+//   - TRequest: The request type that triggers the hook
+//
+// Parameters:
+//   - hook: The function to execute
+//
+// Returns:
+//   - uint64: The ID of the registration, for RemovePublishHookByID
+func AddPublishHook[TRequest any](hook func(request TRequest)) uint64 {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var request TRequest
+	requestType := reflect.TypeOf(request)
+	nextPublishHookID++
+	publishHooks[requestType] = append(publishHooks[requestType], publishHookEntry{
+		id: nextPublishHookID,
+		hook: func(request any) {
+			hook(request.(TRequest))
+		},
+	})
+	return nextPublishHookID
+}
+
+// GetPublishHooks returns the hooks registered for the specified request type.
+//
+// Returns:
+//   - []func(request any): The list of hooks
+func GetPublishHooks[TRequest any]() []func(request any) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var request TRequest
+	entries := publishHooks[reflect.TypeOf(request)]
+	hooks := make([]func(request any), len(entries))
+	for i, entry := range entries {
+		hooks[i] = entry.hook
+	}
+	return hooks
+}
+
+// RemovePublishHookByID unregisters the hook registration with the ID returned by AddPublishHook.
+//
+// Parameters:
+//   - id: The ID of the registration
+//
+// Returns:
+//   - bool: Indicates whether the hook was registered
+func RemovePublishHookByID(id uint64) bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for requestType, entries := range publishHooks {
+		index := slices.IndexFunc(entries, func(entry publishHookEntry) bool { return entry.id == id })
+		if index < 0 {
+			continue
+		}
+		if len(entries) == 1 {
+			delete(publishHooks, requestType)
+		} else {
+			publishHooks[requestType] = slices.Delete(entries, index, index+1)
+		}
+		return true
+	}
+	return false
+}
+
+// RemovePublishHooks unregisters all hooks for the specified request type.
+//
+// Type parameters:
+//   - TRequest: The request type whose hooks should be removed
+func RemovePublishHooks[TRequest any]() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var request TRequest
+	delete(publishHooks, reflect.TypeOf(request))
+}
+
+// SetPublisher records the function publishing requests of the specified type through the
+// mediator. The mediator records it when a subscriber is registered, so that the packages it
+// depends on, such as pipeline, can publish events without importing it.
+//
+// Type parameters:
+//   - TRequest: The request type published by the function
+//
+// Parameters:
+//   - publish: The function publishing a request to its subscribers
+func SetPublisher[TRequest any](publish func(request TRequest, params ...any) error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	publishers[reflect.TypeFor[TRequest]()] = publish
+}
+
+// GetPublisher returns the function publishing requests of the specified type.
+//
+// Type parameters:
+//   - TRequest: The request type to publish
+//
+// Returns:
+//   - func(request TRequest, params ...any) error: The function publishing the request
+//   - bool: Indicates whether a subscriber was ever registered for the request type
+func GetPublisher[TRequest any]() (func(request TRequest, params ...any) error, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	publish, ok := publishers[reflect.TypeFor[TRequest]()].(func(request TRequest, params ...any) error)
+	return publish, ok
+}
+
+// PipelineEntry is a registered pipeline along with its name and order.
+type PipelineEntry struct {
+	Name     string
+	Order    int
+	Pipeline interfaces.Pipeline
+}
+
+// PipelinePlacement tells InsertPipeline where to put a pipeline.
+// Before and After name a registered pipeline; when both are empty the pipeline is placed
+// after every pipeline with a lower or equal order.
+type PipelinePlacement struct {
+	Before string
+	After  string
+}
+
 // AddPipeline registers a pipeline that will be executed before handlers.
-// Pipelines are executed in reverse order of registration (last registered, first executed).
+// Pipelines are executed in order of registration (first registered, first executed),
+// unless they are given an explicit order with InsertPipeline.
 // Use pipelines for cross-cutting concerns like logging, validation, or authentication.
 //
 // Parameters:
 //   - p: The pipeline to register
 func AddPipeline(p interfaces.Pipeline) {
+	_ = InsertPipeline(PipelineEntry{Pipeline: p}, PipelinePlacement{})
+}
+
+// InsertPipeline registers a pipeline at the requested position. A pipeline placed before or
+// after another one takes its order. Pipelines are kept sorted by order, pipelines with
+// the same order run in the order they were inserted.
+//
+// Parameters:
+//   - entry: The pipeline to register, with its unique name (optional) and order
+//   - placement: The registered pipeline to insert it next to, if any
+//
+// Returns:
+//   - error: An error if the name is already registered or the referenced pipeline is not registered
+func InsertPipeline(entry PipelineEntry, placement PipelinePlacement) error {
 	mu.Lock()
 	defer mu.Unlock()
 
-	messagePipelines = append(messagePipelines, p)
+	if entry.Name != "" && indexOfPipeline(entry.Name) >= 0 {
+		return fmt.Errorf(`pipeline "%s" is already registered`, entry.Name)
+	}
+
+	var index int
+	switch {
+	case placement.Before != "":
+		index = indexOfPipeline(placement.Before)
+		if index < 0 {
+			return fmt.Errorf(`pipeline "%s" is not registered`, placement.Before)
+		}
+		entry.Order = messagePipelines[index].Order
+	case placement.After != "":
+		index = indexOfPipeline(placement.After)
+		if index < 0 {
+			return fmt.Errorf(`pipeline "%s" is not registered`, placement.After)
+		}
+		entry.Order = messagePipelines[index].Order
+		index++
+	default:
+		index = len(messagePipelines)
+		for index > 0 && messagePipelines[index-1].Order > entry.Order {
+			index--
+		}
+	}
+
+	messagePipelines = slices.Insert(messagePipelines, index, entry)
+	return nil
+}
+
+// RemovePipeline unregisters the pipeline registered with the name.
+//
+// Parameters:
+//   - name: The name of the pipeline to remove
+//
+// Returns:
+//   - bool: Indicates whether the pipeline was found
+func RemovePipeline(name string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	index := indexOfPipeline(name)
+	if name == "" || index < 0 {
+		return false
+	}
+	messagePipelines = slices.Delete(messagePipelines, index, index+1)
+	return true
+}
+
+// ListPipelines returns the registered pipelines in execution order.
+//
+// Returns:
+//   - []PipelineEntry: The list of registered pipelines
+func ListPipelines() []PipelineEntry {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return slices.Clone(messagePipelines)
 }
 
-// GetPipelines retrieves all registered pipelines.
+// GetPipelines retrieves all registered pipelines in execution order.
 //
 // Returns:
 //   - []interfaces.Pipeline: The list of registered pipelines
@@ -171,7 +686,11 @@ func GetPipelines() []interfaces.Pipeline {
 	mu.RLock()
 	defer mu.RUnlock()
 
-	return messagePipelines
+	pipelines := make([]interfaces.Pipeline, 0, len(messagePipelines))
+	for _, entry := range messagePipelines {
+		pipelines = append(pipelines, entry.Pipeline)
+	}
+	return pipelines
 }
 
 // ClearPipelines removes all registered pipelines.
@@ -179,5 +698,210 @@ func ClearPipelines() {
 	mu.Lock()
 	defer mu.Unlock()
 
-	messagePipelines = make([]interfaces.Pipeline, 0)
+	messagePipelines = make([]PipelineEntry, 0)
+}
+
+// indexOfPipeline returns the position of the named pipeline, or -1. The caller must hold the lock.
+func indexOfPipeline(name string) int {
+	return slices.IndexFunc(messagePipelines, func(entry PipelineEntry) bool {
+		return entry.Name == name
+	})
+}
+
+// SetMetrics sets the metrics recorder of the mediator, nil disables metrics.
+//
+// Parameters:
+//   - m: The metrics recorder
+func SetMetrics(m interfaces.Metrics) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	metrics = m
+}
+
+// GetMetrics returns the metrics recorder of the mediator.
+//
+// Returns:
+//   - interfaces.Metrics: The metrics recorder, or nil if metrics are disabled
+func GetMetrics() interfaces.Metrics {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return metrics
+}
+
+// SetTracer sets the tracer of the mediator, nil disables tracing.
+//
+// Parameters:
+//   - t: The tracer
+func SetTracer(t interfaces.Tracer) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	tracer = t
+}
+
+// GetTracer returns the tracer of the mediator.
+//
+// Returns:
+//   - interfaces.Tracer: The tracer, or nil if tracing is disabled
+func GetTracer() interfaces.Tracer {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return tracer
+}
+
+// observerEntry is a registered observer with the ID identifying its registration.
+type observerEntry struct {
+	id       uint64
+	observer interfaces.Observer
+}
+
+// AddObserver registers an observer of the mediator lifecycle.
+//
+// Parameters:
+//   - observer: The observer to register
+//
+// Returns:
+//   - uint64: The ID of the registration, for RemoveObserverByID
+func AddObserver(observer interfaces.Observer) uint64 {
+	mu.Lock()
+	defer mu.Unlock()
+
+	nextObserverID++
+	observers = append(observers, observerEntry{id: nextObserverID, observer: observer})
+	return nextObserverID
+}
+
+// GetObservers returns the registered observers in registration order.
+//
+// Returns:
+//   - []interfaces.Observer: A copy of the registered observers
+func GetObservers() []interfaces.Observer {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	result := make([]interfaces.Observer, len(observers))
+	for i, entry := range observers {
+		result[i] = entry.observer
+	}
+	return result
+}
+
+// RemoveObserver unregisters the first registration of an observer. Observers are compared
+// with ==, so observers whose type is not comparable are never found; remove them with
+// RemoveObserverByID instead.
+//
+// Parameters:
+//   - observer: The observer to remove
+//
+// Returns:
+//   - bool: Indicates whether the observer was registered
+func RemoveObserver(observer interfaces.Observer) bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return removeObserverAt(slices.IndexFunc(observers, func(entry observerEntry) bool {
+		return sameObserver(entry.observer, observer)
+	}))
+}
+
+// RemoveObserverByID unregisters the observer registration with the ID returned by AddObserver.
+//
+// Parameters:
+//   - id: The ID of the registration
+//
+// Returns:
+//   - bool: Indicates whether the registration existed
+func RemoveObserverByID(id uint64) bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return removeObserverAt(slices.IndexFunc(observers, func(entry observerEntry) bool {
+		return entry.id == id
+	}))
+}
+
+// removeObserverAt removes the observer at the index, if any. The caller must hold the lock.
+func removeObserverAt(index int) bool {
+	if index < 0 {
+		return false
+	}
+	observers = slices.Delete(observers, index, index+1)
+	return true
+}
+
+// sameObserver reports whether two observers are equal, without panicking on observers whose
+// dynamic type is not comparable.
+func sameObserver(a interfaces.Observer, b interfaces.Observer) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if !va.IsValid() || !vb.IsValid() {
+		return a == nil && b == nil
+	}
+	if va.Type() != vb.Type() || !va.Comparable() || !vb.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// AddNotificationPipeline registers a notification pipeline wrapping the subscribers of every event type.
+//
+// Parameters:
+//   - pipeline: The notification pipeline to register
+func AddNotificationPipeline(pipeline interfaces.NotificationPipeline) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	notificationPipelines = append(notificationPipelines, pipeline)
+}
+
+// AddNotificationPipelineFor registers a notification pipeline wrapping the subscribers of
+// the specified event type.
+//
+// Type parameters:
+//   - TRequest: The event type whose subscribers are wrapped
+//
+// Parameters:
+//   - pipeline: The notification pipeline to register
+func AddNotificationPipelineFor[TRequest any](pipeline interfaces.NotificationPipeline) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	requestType := reflect.TypeFor[TRequest]()
+	eventNotificationPipelines[requestType] = append(eventNotificationPipelines[requestType], pipeline)
+}
+
+// GetNotificationPipelines returns the notification pipelines wrapping the subscribers of the
+// specified event type: the global ones first, then the ones of the event type, each in
+// registration order.
+//
+// Returns:
+//   - []interfaces.NotificationPipeline: The notification pipelines
+func GetNotificationPipelines[TRequest any]() []interfaces.NotificationPipeline {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return slices.Concat(notificationPipelines, eventNotificationPipelines[reflect.TypeFor[TRequest]()])
+}
+
+// RemoveNotificationPipelines unregisters the notification pipelines of the specified event type.
+// Global notification pipelines are kept.
+//
+// Type parameters:
+//   - TRequest: The event type whose notification pipelines should be removed
+func RemoveNotificationPipelines[TRequest any]() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	delete(eventNotificationPipelines, reflect.TypeFor[TRequest]())
+}
+
+// ClearNotificationPipelines removes all registered notification pipelines, global and per event type.
+func ClearNotificationPipelines() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	notificationPipelines = make([]interfaces.NotificationPipeline, 0)
+	eventNotificationPipelines = make(map[reflect.Type][]interfaces.NotificationPipeline)
 }
@@ -3,6 +3,11 @@
 // that can be implemented by users to extend the functionality of the mediator.
 package interfaces
 
+import (
+	"context"
+	"time"
+)
+
 // Handler represents a request/response handler in the mediator pattern.
 //
 // Type parameters:
@@ -20,6 +25,83 @@ type Handler[TRequest any, TResponse any] interface {
 	Handle(request TRequest, params ...any) (TResponse, error)
 }
 
+// BatchHandler represents a request/response handler that processes several requests at once.
+// Concurrent Send calls for the same request type are coalesced into a single Handle call,
+// and the response at index i is delivered to the caller that sent requests[i].
+//
+// Type parameters:
+//   - TRequest: The request type that the handler will process
+//   - TResponse: The response type that the handler will return
+//
+// Example:
+//
+//	type GetUsersHandler struct{}
+//	func (h *GetUsersHandler) Handle(reqs []GetUserRequest, params ...any) ([]GetUserResponse, error) {
+//	    // Load all users with a single query
+//	    return users, nil
+//	}
+//	godiator.RegisterBatchHandler[GetUserRequest, GetUserResponse](&GetUsersHandler{})
+type BatchHandler[TRequest any, TResponse any] interface {
+	Handle(requests []TRequest, params ...any) ([]TResponse, error)
+}
+
+// PreProcessor represents a step executed before the handler of a request type.
+// It receives a pointer to the request so that it can mutate or enrich it.
+// Returning an error stops the request before it reaches the handler.
+//
+// Type parameters:
+//   - TRequest: The request type that the pre-processor will process
+//
+// Example:
+//
+//	type NormalizeEmailPreProcessor struct{}
+//	func (p *NormalizeEmailPreProcessor) Process(req *CreateUserRequest, params ...any) error {
+//	    req.Email = strings.ToLower(req.Email)
+//	    return nil
+//	}
+//	godiator.RegisterPreProcessor[CreateUserRequest](&NormalizeEmailPreProcessor{})
+type PreProcessor[TRequest any] interface {
+	Process(request *TRequest, params ...any) error
+}
+
+// PostProcessor represents a step executed after the handler of a request type succeeds.
+// Returning an error makes Send fail with it, along with the handler's response.
+//
+// Type parameters:
+//   - TRequest: The request type that the post-processor will process
+//   - TResponse: The response type returned by the handler
+//
+// Example:
+//
+//	type AuditPostProcessor struct{}
+//	func (p *AuditPostProcessor) Process(req CreateUserRequest, resp CreateUserResponse, params ...any) error {
+//	    return audit.Record("user created", resp.ID)
+//	}
+//	godiator.RegisterPostProcessor[CreateUserRequest, CreateUserResponse](&AuditPostProcessor{})
+type PostProcessor[TRequest any, TResponse any] interface {
+	Process(request TRequest, response TResponse, params ...any) error
+}
+
+// ExceptionHandler represents a fallback for a specific error type returned while sending a request.
+// It is invoked when the error matches TErr with errors.As, and returns the response to use instead
+// of the error along with true, or false to leave the error unhandled.
+//
+// Type parameters:
+//   - TRequest: The request type that the exception handler will process
+//   - TResponse: The response type returned by the handler
+//   - TErr: The error type that the exception handler will handle
+//
+// Example:
+//
+//	type NotFoundExceptionHandler struct{}
+//	func (h *NotFoundExceptionHandler) Handle(req GetUserRequest, err *NotFoundError, params ...any) (GetUserResponse, bool) {
+//	    return GetUserResponse{Name: "Guest"}, true
+//	}
+//	godiator.RegisterExceptionHandler[GetUserRequest, GetUserResponse, *NotFoundError](&NotFoundExceptionHandler{})
+type ExceptionHandler[TRequest any, TResponse any, TErr error] interface {
+	Handle(request TRequest, err TErr, params ...any) (TResponse, bool)
+}
+
 // Subscriber represents a fire-and-forget handler in the mediator pattern.
 //
 // Type parameters:
@@ -36,8 +118,75 @@ type Subscriber[TRequest any] interface {
 	Handle(request TRequest, params ...any)
 }
 
+// FallibleSubscriber represents a subscriber that reports failures by returning an error,
+// so that they can be retried and dead-lettered.
+//
+// Type parameters:
+//   - TRequest: The request type that the subscriber will process
+//
+// Example:
+//
+//	type ProjectionSubscriber struct{}
+//	func (s *ProjectionSubscriber) Handle(req OrderCreated, params ...any) error {
+//	    return db.Insert(req)
+//	}
+//	godiator.RegisterFallibleSubscriber[OrderCreated](&ProjectionSubscriber{}, godiator.WithRetry(policy))
+type FallibleSubscriber[TRequest any] interface {
+	Handle(request TRequest, params ...any) error
+}
+
+// Validator represents a validation rule for a request type.
+// Multiple validators can be registered for the same request type; they are
+// evaluated by the validation pipeline before the handler is invoked.
+//
+// Type parameters:
+//   - TRequest: The request type that the validator will check
+//
+// Example:
+//
+//	type CreateUserValidator struct{}
+//	func (v *CreateUserValidator) Validate(req CreateUserRequest) error {
+//	    if req.Email == "" {
+//	        return pipeline.FieldError{Field: "Email", Message: "is required"}
+//	    }
+//	    return nil
+//	}
+//	godiator.RegisterValidator[CreateUserRequest](&CreateUserValidator{})
+type Validator[TRequest any] interface {
+	Validate(request TRequest) error
+}
+
+// Principal represents the authenticated caller of a request, evaluated by the authorization pipeline.
+//
+// Example:
+//
+//	type User struct{ Permissions []string }
+//	func (u User) HasPermission(permission string) bool {
+//	    return slices.Contains(u.Permissions, permission)
+//	}
+type Principal interface {
+	HasPermission(permission string) bool
+}
+
+// Policy represents an authorization rule for a request type. It returns an error when the
+// principal is not allowed to send the request. The principal is nil for anonymous callers.
+//
+// Type parameters:
+//   - TRequest: The request type that the policy will authorize
+//
+// Example:
+//
+//	godiator.RegisterPolicy(func(ctx context.Context, principal interfaces.Principal, req DeleteOrderCommand) error {
+//	    if principal == nil || !principal.HasPermission("orders:delete") {
+//	        return errors.New("cannot delete orders")
+//	    }
+//	    return nil
+//	})
+type Policy[TRequest any] func(ctx context.Context, principal Principal, request TRequest) error
+
 // Pipeline represents a middleware component in the mediator pattern.
-// Pipelines are executed in reverse order of registration (last registered, first executed).
+// Pipelines are executed in order of registration (first registered, first executed),
+// unless they are registered with an explicit order.
 // Use pipelines for cross-cutting concerns like logging, validation, or authentication.
 //
 // Example:
@@ -55,3 +204,199 @@ type Pipeline interface {
 	SetNext(p Pipeline)
 	Handle(request any, params ...any) (any, error)
 }
+
+// NotificationPipeline represents a middleware component wrapping every subscriber invocation
+// of Publish. It runs inside the goroutine of the subscriber and calls next to continue with
+// the rest of the chain, which ends with the subscriber. next returns the error the subscriber
+// failed with, including the panics of subscribers registered with a retry policy or as
+// fallible subscribers; other panics propagate through next. An event replaced by another
+// type than the subscriber handles fails with an error without reaching the subscriber.
+//
+// Example:
+//
+//	type TenantPipeline struct{}
+//	func (p *TenantPipeline) Handle(event any, next func(event any, params ...any) error, params ...any) error {
+//	    ctx := tenant.With(godiator.ContextFrom(params...), tenantOf(event))
+//	    return next(event, godiator.WithContext(ctx, params...)...)
+//	}
+//	godiator.RegisterNotificationPipeline(&TenantPipeline{})
+type NotificationPipeline interface {
+	Handle(event any, next func(event any, params ...any) error, params ...any) error
+}
+
+// Cache represents a key/value store used to serve responses without reaching the handler.
+// Entries can be associated with tags so that a group of entries can be invalidated at once.
+// Implementations must be safe for concurrent use.
+//
+// Example:
+//
+//	cache := pipeline.NewLRUCache(1000)
+//	godiator.RegisterPipeline(pipeline.NewCaching(cache))
+type Cache interface {
+	Get(key string) (any, bool)
+	Set(key string, value any, ttl time.Duration, tags ...string)
+	Delete(keys ...string)
+	DeleteTags(tags ...string)
+}
+
+// TypedPipeline represents a middleware component for a specific request and response type pair.
+// Unlike Pipeline, it receives the request and the rest of the chain with their concrete types.
+// The params passed to next reach the rest of the chain, so the pipeline can pass on a
+// different context; next returns an error if the chain responds with another response type.
+// It is spliced into the same chain as untyped pipelines, in registration order, and skipped for
+// other request types.
+//
+// Type parameters:
+//   - TRequest: The request type that the pipeline will intercept
+//   - TResponse: The response type returned by the handler
+//
+// Example:
+//
+//	type MaskEmailPipeline struct{}
+//	func (p *MaskEmailPipeline) Handle(req GetUserRequest, next func(GetUserRequest, ...any) (GetUserResponse, error), params ...any) (GetUserResponse, error) {
+//	    resp, err := next(req, params...)
+//	    resp.Email = mask(resp.Email)
+//	    return resp, err
+//	}
+//	godiator.RegisterTypedPipeline[GetUserRequest, GetUserResponse](&MaskEmailPipeline{})
+type TypedPipeline[TRequest any, TResponse any] interface {
+	Handle(request TRequest, next func(TRequest, ...any) (TResponse, error), params ...any) (TResponse, error)
+}
+
+// TypedPipelineFor represents a middleware component for every request implementing an interface.
+// It is spliced into the same chain as untyped pipelines, in registration order, and skipped for
+// requests that do not implement the interface. The params passed to next reach the rest of the chain.
+//
+// Type parameters:
+//   - TInterface: The interface that intercepted requests implement
+//
+// Example:
+//
+//	type Audited interface{ AuditAction() string }
+//	type AuditPipeline struct{}
+//	func (p *AuditPipeline) Handle(req Audited, next func(Audited, ...any) (any, error), params ...any) (any, error) {
+//	    log.Printf("audit: %s", req.AuditAction())
+//	    return next(req, params...)
+//	}
+//	godiator.RegisterPipelineFor[Audited](&AuditPipeline{})
+type TypedPipelineFor[TInterface any] interface {
+	Handle(request TInterface, next func(TInterface, ...any) (any, error), params ...any) (any, error)
+}
+
+// Metrics records the activity of the mediator. Send reports every request and Publish reports
+// every subscriber it runs. Implementations must be safe for concurrent use.
+//
+// Example:
+//
+//	registry := metrics.NewRegistry()
+//	godiator.SetMetrics(registry)
+//	http.Handle("/metrics", registry)
+type Metrics interface {
+	// SendStarted is called when a request is sent.
+	SendStarted(requestType string)
+	// SendFinished is called when a request completes, err is non-nil if it failed or panicked.
+	SendFinished(requestType string, duration time.Duration, err error)
+	// SubscriberStarted is called when a subscriber starts handling a published event.
+	SubscriberStarted(eventType string, subscriber string)
+	// SubscriberFinished is called when a subscriber completes, err is non-nil if it panicked.
+	SubscriberFinished(eventType string, subscriber string, duration time.Duration, err error)
+}
+
+// SpanConfig describes a span started by a Tracer.
+type SpanConfig struct {
+	// Attributes are the attributes of the span, such as the request type.
+	Attributes map[string]string
+	// Links are contexts carrying spans the new span is linked to.
+	Links []context.Context
+	// Root starts a new trace instead of a child of the span found in the context.
+	Root bool
+}
+
+// Span is a unit of work started by a Tracer.
+type Span interface {
+	// SetError marks the span as failed with the error.
+	SetError(err error)
+	// End completes the span.
+	End()
+}
+
+// Tracer starts the spans of the mediator. Send starts a span for the request, with child
+// spans for every pipeline and the handler, and Publish starts a span for the event that the
+// span of every subscriber is linked to. Implementations must be safe for concurrent use.
+//
+// Example:
+//
+//	godiator.SetTracer(otel.NewTracer(otelapi.Tracer("godiator")))
+type Tracer interface {
+	Start(ctx context.Context, name string, config SpanConfig) (context.Context, Span)
+}
+
+// Observer is notified of the lifecycle of the mediator. Observers run outside the pipeline
+// chain and cannot alter requests or responses; panics raised by observers are recovered
+// and ignored. Embed godiator.BaseObserver to implement only the methods of interest.
+//
+// Example:
+//
+//	type SlowRequestObserver struct {
+//	    godiator.BaseObserver
+//	}
+//	func (o *SlowRequestObserver) AfterSend(request any, response any, err error, duration time.Duration) {
+//	    if duration > time.Second {
+//	        log.Printf("slow request %T took %s", request, duration)
+//	    }
+//	}
+//	godiator.RegisterObserver(&SlowRequestObserver{})
+type Observer interface {
+	// BeforeSend is called when a request is sent, before the pipelines.
+	BeforeSend(request any, params ...any)
+	// AfterSend is called when a request completes, err is non-nil if it failed or panicked.
+	AfterSend(request any, response any, err error, duration time.Duration)
+	// BeforePublish is called when an event is published, before any subscriber is started.
+	BeforePublish(event any, params ...any)
+	// SubscriberStarted is called when a subscriber starts handling an event.
+	SubscriberStarted(event any, subscriber string)
+	// SubscriberFinished is called when a subscriber completes.
+	SubscriberFinished(event any, subscriber string, duration time.Duration)
+	// SubscriberFailed is called instead of SubscriberFinished when a subscriber panics.
+	SubscriberFailed(event any, subscriber string, err error)
+	// HandlerRegistered is called when a handler is registered for a request type.
+	HandlerRegistered(requestType string)
+	// HandlerUnregistered is called when the handler of a request type is unregistered.
+	HandlerUnregistered(requestType string)
+}
+
+// EventQueueMetrics can be implemented by a Metrics recorder to also record the event queue
+// enabled with godiator.UseEventQueue.
+type EventQueueMetrics interface {
+	// EventQueueDepth is called whenever the number of queued subscriber invocations changes.
+	EventQueueDepth(depth int, capacity int)
+	// EventDropped is called when a subscriber invocation is dropped because the queue, or the
+	// lane of a partitioned event delivered without the queue, is full.
+	EventDropped(eventType string)
+}
+
+// DeadLetter is a published event that a subscriber failed to handle after every retry.
+type DeadLetter struct {
+	// ID identifies the dead letter within its sink.
+	ID string
+	// EventType is the type of the event.
+	EventType string
+	// Subscriber is the name of the subscriber that failed.
+	Subscriber string
+	// Event is the event itself. Sinks persisting dead letters may return it as json.RawMessage.
+	Event any
+	// Error is the message of the last error.
+	Error string
+	// Attempts is the number of times the subscriber was invoked.
+	Attempts int
+	// FailedAt is when the last attempt failed.
+	FailedAt time.Time
+}
+
+// DeadLetterSink stores dead letters until they are re-driven. Implementations must be safe
+// for concurrent use.
+type DeadLetterSink interface {
+	Put(letter DeadLetter) error
+	List() ([]DeadLetter, error)
+	Delete(id string) error
+}
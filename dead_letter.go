@@ -0,0 +1,267 @@
+package godiator
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/baranius/godiator/core/contexts"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+// ErrDeadLetterNotFound is returned by Redrive when the sink has no dead letter with the ID.
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// RetryPolicy decides how often a failing subscriber is retried.
+type RetryPolicy struct {
+	// MaxAttempts is the number of invocations, including the first one. Values below 2 disable retries.
+	MaxAttempts int
+	// Backoff returns how long to wait after the failed attempt, starting at 1. Nil retries immediately.
+	Backoff func(attempt int) time.Duration
+}
+
+// ExponentialBackoff returns a backoff doubling after every attempt, starting at initial and capped at maxDelay.
+//
+// Parameters:
+//   - initial: The delay after the first attempt
+//   - maxDelay: The maximum delay
+//
+// Returns:
+//   - func(attempt int) time.Duration: The backoff
+//
+// Example:
+//
+//	godiator.WithRetry(godiator.RetryPolicy{MaxAttempts: 5, Backoff: godiator.ExponentialBackoff(100*time.Millisecond, 5*time.Second)})
+func ExponentialBackoff(initial time.Duration, maxDelay time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		delay := initial
+		for i := 1; i < attempt && delay < maxDelay; i++ {
+			delay *= 2
+		}
+		return min(delay, maxDelay)
+	}
+}
+
+// resilientSubscriber retries a failing subscriber and sends the events it keeps failing on
+// to a dead letter sink.
+type resilientSubscriber[TRequest any] struct {
+	handle  func(TRequest, ...any) error
+	options subscriberOptions
+}
+
+// SubscriberName reports the name of the decorated subscriber to the core registry.
+func (s *resilientSubscriber[TRequest]) SubscriberName() string {
+	return s.options.name
+}
+
+// Handle invokes the subscriber like HandleFallible, ignoring the error.
+func (s *resilientSubscriber[TRequest]) Handle(request TRequest, params ...any) {
+	_ = s.HandleFallible(request, params...)
+}
+
+// HandleFallible invokes the subscriber with the context of the params detached from
+// cancellation, so that a Publish context cancelled once Publish returns does not cut the
+// retries short. Once the retries are exhausted, it dead-letters the event and returns the
+// error or panic the subscriber last failed with, for the metrics recorder, the tracer and the
+// observers to report.
+func (s *resilientSubscriber[TRequest]) HandleFallible(request TRequest, params ...any) error {
+	attempts, err := s.attempt(request, contexts.Detach(params)...)
+	if err == nil {
+		return nil
+	}
+
+	eventType := reflect.TypeFor[TRequest]().String()
+	if s.options.sink == nil {
+		slog.Error("subscriber failed", slog.String("event_type", eventType), slog.String("subscriber", s.options.name),
+			slog.Int("attempts", attempts), slog.String("error", err.Error()))
+		return err
+	}
+	letter := interfaces.DeadLetter{
+		ID:         newDeadLetterID(),
+		EventType:  eventType,
+		Subscriber: s.options.name,
+		Event:      request,
+		Error:      err.Error(),
+		Attempts:   attempts,
+		FailedAt:   time.Now(),
+	}
+	if putErr := s.options.sink.Put(letter); putErr != nil {
+		slog.Error("dead letter lost", slog.String("event_type", eventType), slog.String("subscriber", s.options.name),
+			slog.String("error", err.Error()), slog.String("sink_error", putErr.Error()))
+	}
+	return err
+}
+
+// attempt invokes the subscriber until it succeeds or the retry policy is exhausted, or the
+// context found in params is done.
+func (s *resilientSubscriber[TRequest]) attempt(request TRequest, params ...any) (int, error) {
+	ctx := ContextFrom(params...)
+	maxAttempts := max(s.options.retry.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		err := s.invoke(request, params...)
+		if err == nil || attempt >= maxAttempts {
+			return attempt, err
+		}
+		var delay time.Duration
+		if s.options.retry.Backoff != nil {
+			delay = s.options.retry.Backoff(attempt)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return attempt, err
+		}
+	}
+}
+
+// invoke calls the subscriber once, turning a panic into an error.
+func (s *resilientSubscriber[TRequest]) invoke(request TRequest, params ...any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return s.handle(request, params...)
+}
+
+// redrive invokes the subscriber with the event of a dead letter through the notification
+// pipelines of the event type, retrying it per the policy.
+func (s *resilientSubscriber[TRequest]) redrive(event any, params ...any) error {
+	var request TRequest
+	switch value := event.(type) {
+	case TRequest:
+		request = value
+	case json.RawMessage:
+		if err := json.Unmarshal(value, &request); err != nil {
+			return fmt.Errorf("decoding dead letter event: %w", err)
+		}
+	default:
+		return fmt.Errorf("dead letter event is a %T, not a %s", event, reflect.TypeFor[TRequest]())
+	}
+	redriven := &redrivenSubscriber[TRequest]{subscriber: s}
+	handleSubscriber[TRequest](redriven, request, params...)
+	if !redriven.delivered {
+		return errors.New("dead letter event was not delivered by the notification pipelines")
+	}
+	return redriven.err
+}
+
+// redrivenSubscriber runs a re-driven event like a published one, reporting it to the metrics
+// recorder, the tracer and the observers, and keeps the error of the subscriber for Redrive.
+type redrivenSubscriber[TRequest any] struct {
+	subscriber *resilientSubscriber[TRequest]
+	delivered  bool
+	err        error
+}
+
+func (r *redrivenSubscriber[TRequest]) Name() string {
+	return r.subscriber.options.name
+}
+
+func (r *redrivenSubscriber[TRequest]) Handle(event any, params ...any) {
+	_ = r.HandleFallible(event, params...)
+}
+
+func (r *redrivenSubscriber[TRequest]) HandleFallible(event any, params ...any) error {
+	r.delivered = true
+	_, r.err = r.subscriber.attempt(event.(TRequest), params...)
+	return r.err
+}
+
+type redriver interface {
+	redrive(event any, params ...any) error
+}
+
+var (
+	redriversMu sync.RWMutex
+	redrivers   = make(map[string]map[string]redriver)
+)
+
+// setRedriver records the subscriber that re-drives the dead letters of the event type with its
+// name. It panics if another subscriber of the event type already dead-letters under the name,
+// since Redrive could not tell their dead letters apart.
+func setRedriver[TRequest any](name string, subscriber redriver) {
+	redriversMu.Lock()
+	defer redriversMu.Unlock()
+
+	eventType := reflect.TypeFor[TRequest]().String()
+	if _, ok := redrivers[eventType][name]; ok {
+		panic(fmt.Sprintf(`godiator: subscriber "%s" of %s already has a dead letter sink; name it with WithSubscriberName`, name, eventType))
+	}
+	if redrivers[eventType] == nil {
+		redrivers[eventType] = make(map[string]redriver)
+	}
+	redrivers[eventType][name] = subscriber
+}
+
+// removeRedrivers forgets the subscribers of the event type.
+func removeRedrivers[TRequest any]() {
+	redriversMu.Lock()
+	defer redriversMu.Unlock()
+
+	delete(redrivers, reflect.TypeFor[TRequest]().String())
+}
+
+func getRedriver(eventType string, name string) (redriver, bool) {
+	redriversMu.RLock()
+	defer redriversMu.RUnlock()
+
+	subscriber, ok := redrivers[eventType][name]
+	return subscriber, ok
+}
+
+// Redrive hands a dead letter back to the subscriber that failed to handle it, applying its
+// retry policy again. The event goes through the notification pipelines, metrics, tracer and
+// observers like a published event, but synchronously and with the params of Redrive, whose
+// context stops the retries. The dead letter is deleted from the sink once the subscriber
+// succeeds. The subscriber must still be registered under the same name.
+//
+// Parameters:
+//   - sink: The sink holding the dead letter
+//   - id: The ID of the dead letter
+//   - params: Optional additional parameters passed to the subscriber
+//
+// Returns:
+//   - error: ErrDeadLetterNotFound, an error if the subscriber is not registered or a notification
+//     pipeline does not deliver the event, the error of the subscriber, or an error from the sink
+//
+// Example:
+//
+//	letters, _ := sink.List()
+//	for _, letter := range letters {
+//	    if err := godiator.Redrive(sink, letter.ID); err != nil {
+//	        log.Printf("redrive %s: %v", letter.ID, err)
+//	    }
+//	}
+func Redrive(sink interfaces.DeadLetterSink, id string, params ...any) error {
+	letters, err := sink.List()
+	if err != nil {
+		return err
+	}
+	for _, letter := range letters {
+		if letter.ID != id {
+			continue
+		}
+		subscriber, ok := getRedriver(letter.EventType, letter.Subscriber)
+		if !ok {
+			return fmt.Errorf(`subscriber "%s" is not registered for "%s"`, letter.Subscriber, letter.EventType)
+		}
+		if err := subscriber.redrive(letter.Event, params...); err != nil {
+			return err
+		}
+		return sink.Delete(id)
+	}
+	return ErrDeadLetterNotFound
+}
+
+func newDeadLetterID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
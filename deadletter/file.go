@@ -0,0 +1,183 @@
+package deadletter
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var _ interfaces.DeadLetterSink = (*FileSink)(nil)
+
+// fileRecord is a line of a FileSink.
+type fileRecord struct {
+	ID         string          `json:"id"`
+	EventType  string          `json:"event_type"`
+	Subscriber string          `json:"subscriber"`
+	Event      json.RawMessage `json:"event"`
+	Error      string          `json:"error"`
+	Attempts   int             `json:"attempts"`
+	FailedAt   time.Time       `json:"failed_at"`
+}
+
+// FileSink is a DeadLetterSink appending dead letters to a JSON Lines file, so that they
+// survive restarts. Events are encoded with encoding/json and listed as json.RawMessage,
+// which godiator.Redrive decodes into the event type of the subscriber.
+type FileSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileSink creates a FileSink writing to the file, creating its directory if needed.
+//
+// Parameters:
+//   - path: The path of the JSON Lines file
+//
+// Returns:
+//   - *FileSink: The created sink
+//   - error: An error if the directory cannot be created
+func NewFileSink(path string) (*FileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	return &FileSink{path: path}, nil
+}
+
+// Put appends the dead letter to the file.
+//
+// Parameters:
+//   - letter: The dead letter to store
+//
+// Returns:
+//   - error: An error if the event cannot be encoded or the file cannot be written
+func (s *FileSink) Put(letter interfaces.DeadLetter) error {
+	event, err := json.Marshal(letter.Event)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(fileRecord{
+		ID:         letter.ID,
+		EventType:  letter.EventType,
+		Subscriber: letter.Subscriber,
+		Event:      event,
+		Error:      letter.Error,
+		Attempts:   letter.Attempts,
+		FailedAt:   letter.FailedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// List reads the dead letters from the file, oldest first.
+//
+// Returns:
+//   - []interfaces.DeadLetter: The stored dead letters, with events as json.RawMessage
+//   - error: An error if the file cannot be read or decoded
+func (s *FileSink) List() ([]interfaces.DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	letters := make([]interfaces.DeadLetter, len(records))
+	for i, record := range records {
+		letters[i] = interfaces.DeadLetter{
+			ID:         record.ID,
+			EventType:  record.EventType,
+			Subscriber: record.Subscriber,
+			Event:      record.Event,
+			Error:      record.Error,
+			Attempts:   record.Attempts,
+			FailedAt:   record.FailedAt,
+		}
+	}
+	return letters, nil
+}
+
+// Delete removes the dead letter with the ID, if any. The file is rewritten to a temporary
+// location first so that it is never left partially written.
+//
+// Parameters:
+//   - id: The ID of the dead letter
+//
+// Returns:
+//   - error: An error if the file cannot be read or written
+func (s *FileSink) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(s.path), ".deadletters-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	encoder := json.NewEncoder(file)
+	for _, record := range records {
+		if record.ID == id {
+			continue
+		}
+		if err := encoder.Encode(record); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), s.path)
+}
+
+// read decodes every line of the file. The caller must hold the lock.
+func (s *FileSink) read() ([]fileRecord, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []fileRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record fileRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
@@ -0,0 +1,70 @@
+// Package deadletter provides reference implementations of the godiator DeadLetterSink interface.
+package deadletter
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var _ interfaces.DeadLetterSink = (*MemorySink)(nil)
+
+// MemorySink is a DeadLetterSink keeping dead letters in memory.
+// Dead letters are lost when the process exits.
+type MemorySink struct {
+	mu      sync.RWMutex
+	letters []interfaces.DeadLetter
+}
+
+// NewMemorySink creates an empty MemorySink.
+//
+// Returns:
+//   - *MemorySink: The created sink
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+// Put stores the dead letter.
+//
+// Parameters:
+//   - letter: The dead letter to store
+//
+// Returns:
+//   - error: Always nil
+func (s *MemorySink) Put(letter interfaces.DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.letters = append(s.letters, letter)
+	return nil
+}
+
+// List returns the stored dead letters, oldest first.
+//
+// Returns:
+//   - []interfaces.DeadLetter: A copy of the stored dead letters
+//   - error: Always nil
+func (s *MemorySink) List() ([]interfaces.DeadLetter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.letters), nil
+}
+
+// Delete removes the dead letter with the ID, if any.
+//
+// Parameters:
+//   - id: The ID of the dead letter
+//
+// Returns:
+//   - error: Always nil
+func (s *MemorySink) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.letters = slices.DeleteFunc(s.letters, func(letter interfaces.DeadLetter) bool {
+		return letter.ID == id
+	})
+	return nil
+}
@@ -0,0 +1,73 @@
+package godiator
+
+import (
+	"reflect"
+	"sync"
+)
+
+// DispatchMode controls how Publish runs the subscribers of an event.
+type DispatchMode int
+
+const (
+	// DispatchConcurrent runs every subscriber of the event concurrently. This is the default.
+	DispatchConcurrent DispatchMode = iota
+	// DispatchSequential runs the subscribers of the event one after the other, in descending
+	// order of the priority set WithPriority, each starting once the previous one returns. The
+	// sequence runs asynchronously like concurrent subscribers, on the event queue when enabled.
+	DispatchSequential
+)
+
+var (
+	dispatchModesMu sync.RWMutex
+	dispatchModes   = make(map[reflect.Type]DispatchMode)
+)
+
+// SetDispatchMode sets how Publish runs the subscribers of the event type.
+//
+// Type parameters:
+//   - TRequest: The event type
+//
+// Parameters:
+//   - mode: The dispatch mode of the event type
+//
+// Example:
+//
+//	godiator.RegisterSubscriber[ProductUpdated](&CacheInvalidator{}, godiator.WithPriority(10))
+//	godiator.RegisterSubscriber[ProductUpdated](&WebhookNotifier{})
+//	godiator.SetDispatchMode[ProductUpdated](godiator.DispatchSequential)
+func SetDispatchMode[TRequest any](mode DispatchMode) {
+	dispatchModesMu.Lock()
+	defer dispatchModesMu.Unlock()
+
+	requestType := reflect.TypeFor[TRequest]()
+	if mode == DispatchConcurrent {
+		delete(dispatchModes, requestType)
+	} else {
+		dispatchModes[requestType] = mode
+	}
+}
+
+func dispatchModeOf[TRequest any]() DispatchMode {
+	dispatchModesMu.RLock()
+	defer dispatchModesMu.RUnlock()
+
+	return dispatchModes[reflect.TypeFor[TRequest]()]
+}
+
+// sequence combines the subscriber invocations of an event into a single invocation running
+// them one after the other. The sequence has no subscriber name, so that the sequences of
+// partitioned events share a lane per event type and key.
+func sequence(invocations []queuedInvocation) queuedInvocation {
+	combined := queuedInvocation{
+		eventType: invocations[0].eventType,
+		run: func() {
+			for _, invocation := range invocations {
+				invocation.execute()
+			}
+		},
+	}
+	for _, invocation := range invocations {
+		combined.workIDs = append(combined.workIDs, invocation.workIDs...)
+	}
+	return combined
+}
@@ -0,0 +1,412 @@
+package godiator
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/core/contexts"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+// ErrEventQueueFull is returned by Publish when the event queue is full and its overflow
+// policy is OverflowReturnError.
+var ErrEventQueueFull = errors.New("event queue is full")
+
+// OverflowPolicy decides what Publish does when the event queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits until the queue has room, the context found in params is done, or
+	// the queue is replaced. Subscribers running on the workers do not wait when they publish
+	// with the params they received: the invocations that find the queue full run on their
+	// worker straight away, ahead of queued invocations of their partition, since waiting could
+	// leave no worker to make room.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest queued subscriber invocations to make room.
+	OverflowDropOldest
+	// OverflowDropNewest discards the published event.
+	OverflowDropNewest
+	// OverflowReturnError discards the published event and returns ErrEventQueueFull.
+	OverflowReturnError
+)
+
+// EventQueueConfig configures the event queue enabled with UseEventQueue.
+type EventQueueConfig struct {
+	// Capacity is the number of subscriber invocations the queue holds. Defaults to 1024.
+	// Invocations of Partitioned events wait in a queue per worker instead, holding
+	// Capacity/Workers invocations each.
+	Capacity int
+	// Workers is the number of goroutines running subscribers. Defaults to runtime.NumCPU().
+	Workers int
+	// Overflow is what Publish does when the queue is full. Defaults to OverflowBlock.
+	Overflow OverflowPolicy
+}
+
+// EventQueueStats describes the current load of the event queue.
+type EventQueueStats struct {
+	Depth    int
+	Capacity int
+	// Partitioned is the number of invocations of Partitioned events waiting in the queues of
+	// the workers.
+	Partitioned int
+	Workers     int
+	Enqueued    uint64
+	Processed   uint64
+	Dropped     uint64
+}
+
+// queuedInvocation is a subscriber invocation waiting in the event queue, or a sequence of
+// them for events dispatched with DispatchSequential.
+type queuedInvocation struct {
+	eventType  string
+	subscriber string
+	// lane is set for partitioned events: invocations sharing a lane run in publish order
+	lane    string
+	workIDs []uint64
+	run     func()
+}
+
+// execute runs the invocation and marks its work as done.
+func (i queuedInvocation) execute() {
+	defer i.end()
+	i.run()
+}
+
+// end marks the work of the invocation as done.
+func (i queuedInvocation) end() {
+	for _, id := range i.workIDs {
+		inFlight.end(id)
+	}
+}
+
+// eventQueue runs subscriber invocations on a fixed pool of workers.
+type eventQueue struct {
+	config      EventQueueConfig
+	invocations chan queuedInvocation
+	// partitions holds the partitioned invocations of each worker, every lane being assigned
+	// to a single worker
+	partitions []chan queuedInvocation
+	workers    sync.WaitGroup
+
+	// closeMu is held for writing to close the channel, and for reading while enqueueing
+	closeMu sync.RWMutex
+	closed  bool
+	// done is closed first by close, to wake the publishers waiting for room
+	done     chan struct{}
+	doneOnce sync.Once
+	// enqueueMu makes enqueueing all the invocations of an event atomic for non-blocking policies
+	enqueueMu sync.Mutex
+
+	enqueued  atomic.Uint64
+	processed atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+var (
+	eventQueueMu sync.RWMutex
+	activeQueue  *eventQueue
+)
+
+// UseEventQueue makes Publish hand subscriber invocations to a bounded queue served by a fixed
+// pool of workers, instead of starting a goroutine per subscriber. Calling it again replaces the
+// queue; invocations already queued still run.
+//
+// Parameters:
+//   - config: The capacity, number of workers and overflow policy of the queue
+//
+// Example:
+//
+//	godiator.UseEventQueue(godiator.EventQueueConfig{Capacity: 10_000, Workers: 16, Overflow: godiator.OverflowDropOldest})
+func UseEventQueue(config EventQueueConfig) {
+	if config.Capacity <= 0 {
+		config.Capacity = 1024
+	}
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+	queue := &eventQueue{
+		config:      config,
+		invocations: make(chan queuedInvocation, config.Capacity),
+		partitions:  make([]chan queuedInvocation, config.Workers),
+		done:        make(chan struct{}),
+	}
+	for i := range config.Workers {
+		queue.partitions[i] = make(chan queuedInvocation, max(1, config.Capacity/config.Workers))
+		queue.workers.Add(1)
+		go queue.work(queue.partitions[i])
+	}
+
+	eventQueueMu.Lock()
+	previous := activeQueue
+	activeQueue = queue
+	eventQueueMu.Unlock()
+
+	if previous != nil {
+		previous.close()
+	}
+}
+
+// DisableEventQueue makes Publish start a goroutine per subscriber again, which is the default.
+// Invocations already queued still run.
+func DisableEventQueue() {
+	eventQueueMu.Lock()
+	previous := activeQueue
+	activeQueue = nil
+	eventQueueMu.Unlock()
+
+	if previous != nil {
+		previous.close()
+	}
+}
+
+// QueueStats returns the current load of the event queue.
+//
+// Returns:
+//   - EventQueueStats: The load of the queue
+//   - bool: Indicates whether the event queue is enabled
+//
+// Example:
+//
+//	if stats, ok := godiator.QueueStats(); ok {
+//	    log.Printf("%d/%d queued, %d dropped", stats.Depth, stats.Capacity, stats.Dropped)
+//	}
+func QueueStats() (EventQueueStats, bool) {
+	queue := currentEventQueue()
+	if queue == nil {
+		return EventQueueStats{}, false
+	}
+	return queue.stats(), true
+}
+
+func currentEventQueue() *eventQueue {
+	eventQueueMu.RLock()
+	defer eventQueueMu.RUnlock()
+
+	return activeQueue
+}
+
+func (q *eventQueue) stats() EventQueueStats {
+	stats := EventQueueStats{
+		Depth:     len(q.invocations),
+		Capacity:  q.config.Capacity,
+		Workers:   q.config.Workers,
+		Enqueued:  q.enqueued.Load(),
+		Processed: q.processed.Load(),
+		Dropped:   q.dropped.Load(),
+	}
+	for _, partition := range q.partitions {
+		stats.Partitioned += len(partition)
+	}
+	return stats
+}
+
+// work runs queued invocations, and the partitioned invocations assigned to the worker in order,
+// until the queue is closed and drained.
+func (q *eventQueue) work(partition chan queuedInvocation) {
+	defer q.workers.Done()
+
+	invocations := q.invocations
+	for invocations != nil || partition != nil {
+		var invocation queuedInvocation
+		var ok bool
+		select {
+		case invocation, ok = <-invocations:
+			if !ok {
+				invocations = nil
+				continue
+			}
+			q.reportDepth()
+		case invocation, ok = <-partition:
+			if !ok {
+				partition = nil
+				continue
+			}
+		}
+		q.run(invocation)
+	}
+}
+
+func (q *eventQueue) run(invocation queuedInvocation) {
+	defer q.processed.Add(1)
+	invocation.execute()
+}
+
+// queuedKey marks the context passed to the subscribers run by the workers of the event queue.
+type queuedKey struct{}
+
+// markQueued returns the params with a context marked as delivered by the event queue, so that
+// the events the subscribers publish are not left waiting for their own worker.
+func markQueued(params []any) []any {
+	return contexts.With(context.WithValue(ContextFrom(params...), queuedKey{}, true), params...)
+}
+
+// close stops accepting invocations and lets the workers exit once the queue is drained.
+func (q *eventQueue) close() {
+	// Publishers waiting for room hold closeMu for reading until woken
+	q.doneOnce.Do(func() { close(q.done) })
+
+	q.closeMu.Lock()
+	defer q.closeMu.Unlock()
+
+	if !q.closed {
+		q.closed = true
+		close(q.invocations)
+		for _, partition := range q.partitions {
+			close(partition)
+		}
+	}
+}
+
+// queueOf returns the queue of the invocation: the queue of the worker its lane is assigned to
+// for partitioned events, the shared queue otherwise.
+func (q *eventQueue) queueOf(invocation queuedInvocation) chan queuedInvocation {
+	if invocation.lane == "" {
+		return q.invocations
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(invocation.lane))
+	return q.partitions[hash.Sum32()%uint32(len(q.partitions))]
+}
+
+// enqueue queues the invocations of a published event according to the overflow policy.
+// Invocations that are not queued are no longer tracked as in flight.
+func (q *eventQueue) enqueue(params []any, invocations []queuedInvocation) error {
+	overflow, err := q.offer(params, invocations)
+	// Run once closeMu is released, so that replacing the queue does not wait for them
+	for _, invocation := range overflow {
+		q.run(invocation)
+	}
+	return err
+}
+
+// offer queues the invocations and returns those published by a subscriber running on a worker
+// that found the queue full under OverflowBlock, to be run by the caller.
+func (q *eventQueue) offer(params []any, invocations []queuedInvocation) ([]queuedInvocation, error) {
+	q.closeMu.RLock()
+	defer q.closeMu.RUnlock()
+
+	if q.closed {
+		// Replaced while publishing, the invocations still run
+		return nil, runUnqueued(params, invocations)
+	}
+
+	switch q.config.Overflow {
+	case OverflowBlock:
+		ctx := ContextFrom(params...)
+		if ctx.Value(queuedKey{}) != nil {
+			var overflow []queuedInvocation
+			for _, invocation := range invocations {
+				if !q.trySend(invocation) {
+					overflow = append(overflow, invocation)
+				}
+			}
+			q.reportDepth()
+			return overflow, nil
+		}
+		for i, invocation := range invocations {
+			select {
+			case q.queueOf(invocation) <- invocation:
+				q.enqueued.Add(1)
+			case <-ctx.Done():
+				q.discard(invocations[i:])
+				return nil, ctx.Err()
+			case <-q.done:
+				// Replaced while waiting for room, the remaining invocations still run
+				return nil, runUnqueued(params, invocations[i:])
+			}
+		}
+	case OverflowDropOldest:
+		q.enqueueMu.Lock()
+		defer q.enqueueMu.Unlock()
+
+		for _, invocation := range invocations {
+			for !q.trySend(invocation) {
+				select {
+				case oldest := <-q.queueOf(invocation):
+					q.discard([]queuedInvocation{oldest})
+				default:
+				}
+			}
+		}
+	default:
+		q.enqueueMu.Lock()
+		defer q.enqueueMu.Unlock()
+
+		// Workers only take invocations out, so the room cannot shrink while holding enqueueMu
+		needed := make(map[chan queuedInvocation]int)
+		for _, invocation := range invocations {
+			needed[q.queueOf(invocation)]++
+		}
+		for queue, n := range needed {
+			if cap(queue)-len(queue) < n {
+				q.discard(invocations)
+				if q.config.Overflow == OverflowReturnError {
+					return nil, ErrEventQueueFull
+				}
+				return nil, nil
+			}
+		}
+		for _, invocation := range invocations {
+			q.trySend(invocation)
+		}
+	}
+	q.reportDepth()
+	return nil, nil
+}
+
+// runUnqueued runs invocations without the event queue: partitioned invocations on their lane,
+// waiting for room until the context found in params is done, the others on a goroutine each.
+// Invocations that are not run once the context is done are no longer tracked as in flight.
+func runUnqueued(params []any, invocations []queuedInvocation) error {
+	for i, invocation := range invocations {
+		if invocation.lane == "" {
+			go invocation.execute()
+			continue
+		}
+		if err := deliverPartitioned(invocation, params); err != nil {
+			reportDropped(invocations[i:])
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *eventQueue) trySend(invocation queuedInvocation) bool {
+	select {
+	case q.queueOf(invocation) <- invocation:
+		q.enqueued.Add(1)
+		return true
+	default:
+		return false
+	}
+}
+
+// discard drops invocations that will not run.
+func (q *eventQueue) discard(invocations []queuedInvocation) {
+	q.dropped.Add(uint64(len(invocations)))
+	reportDropped(invocations)
+}
+
+// reportDropped marks the work of invocations that will not run as done and reports them to
+// the metrics recorder, if it records queues.
+func reportDropped(invocations []queuedInvocation) {
+	metrics, _ := core.GetMetrics().(interfaces.EventQueueMetrics)
+	for _, invocation := range invocations {
+		invocation.end()
+		if metrics != nil {
+			metrics.EventDropped(invocation.eventType)
+		}
+	}
+}
+
+// reportDepth reports the depth of the queue to the metrics recorder, if it records queues.
+func (q *eventQueue) reportDepth() {
+	if metrics, ok := core.GetMetrics().(interfaces.EventQueueMetrics); ok {
+		metrics.EventQueueDepth(len(q.invocations), q.config.Capacity)
+	}
+}
@@ -9,5 +9,5 @@ type executionPipeline struct {
 }
 
 func (ep *executionPipeline) Handle(request any, params ...any) (any, error) {
-	return ep.wrapperFunc(request, params)
+	return ep.wrapperFunc(request, params...)
 }
@@ -0,0 +1,84 @@
+package godiator
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+// WithFilter delivers only the events matching the predicate to the subscriber. The predicate
+// is evaluated synchronously by Publish before the subscriber is scheduled, so filtered out events cost
+// neither a goroutine nor a slot in the event queue. The event type of the predicate must match
+// the event type of the subscriber, otherwise registration panics.
+//
+// Type parameters:
+//   - TRequest: The event type of the subscriber
+//
+// Parameters:
+//   - filter: Reports whether the subscriber should receive the event
+//
+// Example:
+//
+//	godiator.RegisterSubscriber[PaymentProcessed](&AlertSubscriber{},
+//	    godiator.WithFilter(func(e PaymentProcessed) bool { return e.Status == "failed" }))
+func WithFilter[TRequest any](filter func(TRequest) bool) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.filter = filter
+	}
+}
+
+// filteredSubscriber wraps a subscriber with the predicate selecting the events it receives.
+type filteredSubscriber[TRequest any] struct {
+	subscriber interfaces.Subscriber[TRequest]
+	filter     func(TRequest) bool
+}
+
+// subscriberFilter returns the filter of the registration options, or nil if there is none.
+func subscriberFilter[TRequest any](options subscriberOptions) func(TRequest) bool {
+	if options.filter == nil {
+		return nil
+	}
+	filter, ok := options.filter.(func(TRequest) bool)
+	if !ok {
+		panic(fmt.Sprintf("godiator: filter %T does not match the event type %s", options.filter, reflect.TypeFor[TRequest]()))
+	}
+	return filter
+}
+
+// filterSubscriber wraps the subscriber with the filter, if any.
+func filterSubscriber[TRequest any](subscriber interfaces.Subscriber[TRequest], filter func(TRequest) bool) interfaces.Subscriber[TRequest] {
+	if filter == nil {
+		return subscriber
+	}
+	return &filteredSubscriber[TRequest]{subscriber: subscriber, filter: filter}
+}
+
+// Handle runs the wrapped subscriber.
+func (f *filteredSubscriber[TRequest]) Handle(request TRequest, params ...any) {
+	f.subscriber.Handle(request, params...)
+}
+
+// HandleFallible runs the wrapped subscriber and returns the error it failed with, if it reports one.
+func (f *filteredSubscriber[TRequest]) HandleFallible(request TRequest, params ...any) error {
+	if fallible, ok := f.subscriber.(interface {
+		HandleFallible(TRequest, ...any) error
+	}); ok {
+		return fallible.HandleFallible(request, params...)
+	}
+	f.subscriber.Handle(request, params...)
+	return nil
+}
+
+// Accepts reports whether the event matches the filter.
+func (f *filteredSubscriber[TRequest]) Accepts(request TRequest) bool {
+	return f.filter(request)
+}
+
+// SubscriberName returns the name of the wrapped subscriber.
+func (f *filteredSubscriber[TRequest]) SubscriberName() string {
+	if named, ok := f.subscriber.(interface{ SubscriberName() string }); ok {
+		return named.SubscriberName()
+	}
+	return fmt.Sprintf("%T", f.subscriber)
+}
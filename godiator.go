@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"reflect"
 	"slices"
+	"time"
 
 	"github.com/baranius/godiator/core"
 	"github.com/baranius/godiator/core/interfaces"
@@ -32,7 +33,8 @@ import (
 
 // RegisterHandler registers a handler for a specific request and response type pair.
 // Only one handler can be registered per request type. If a handler already exists
-// for the request type, it will be replaced.
+// for the request type, it will be replaced. Options such as WithSingleflight and
+// WithMaxConcurrency change how the handler is executed.
 //
 // Type parameters:
 //   - TRequest: The request type that the handler will process
@@ -45,13 +47,45 @@ import (
 //	    return GetUserResponse{Name: "John"}, nil
 //	}
 //	godiator.RegisterHandler[GetUserRequest, GetUserResponse](&GetUserHandler{})
-func RegisterHandler[TRequest any, TResponse any](handler interfaces.Handler[TRequest, TResponse]) {
-	core.AddHandler[TRequest, TResponse](handler)
+func RegisterHandler[TRequest any, TResponse any](handler interfaces.Handler[TRequest, TResponse], opts ...HandlerOption) {
+	core.AddHandler[TRequest, TResponse](decorateHandler(handler, opts...))
+	setHandlerCloser[TRequest](handler)
+	notifyHandlerRegistered[TRequest]()
+}
+
+// RegisterBatchHandler registers a batch handler for a specific request and response type pair.
+// Concurrent Send calls for the request type are coalesced into a single handler invocation,
+// dispatched when the batch window expires or the maximum batch size is reached. Each caller
+// receives the response at its own position in the batch. The batch handler replaces any
+// handler already registered for the request type.
+//
+// Only requests sent with the same principal (see ContextWithPrincipal) and the same
+// transaction (see pipeline.ContextWithTx) share a batch, so that a batch is handled on behalf
+// of a single identity. Its other params, including the context, are those of the first
+// request of the batch: do not rely on other values passed by the other callers.
+//
+// Type parameters:
+//   - TRequest: The request type that the handler will process
+//   - TResponse: The response type that the handler will return
+//
+// Example:
+//
+//	godiator.RegisterBatchHandler[GetUserRequest, GetUserResponse](&GetUsersHandler{},
+//	    godiator.WithBatchWindow(5*time.Millisecond),
+//	    godiator.WithMaxBatchSize(50),
+//	)
+func RegisterBatchHandler[TRequest any, TResponse any](handler interfaces.BatchHandler[TRequest, TResponse], opts ...BatchOption) {
+	core.AddHandler[TRequest, TResponse](newBatchHandler(handler, opts...))
+	setBulkhead[TRequest](nil)
+	setHandlerCloser[TRequest](handler)
+	notifyHandlerRegistered[TRequest]()
 }
 
 // RegisterSubscriber registers a subscriber for a specific request type.
 // Multiple subscribers can be registered for the same request type.
 // Subscribers are executed asynchronously when Publish is called.
+// Options can retry the subscriber when it panics and dead-letter the events it keeps failing on,
+// set its priority among the subscribers of the event, and filter the events it receives.
 //
 // Type parameters:
 //   - TRequest: The request type that the subscriber will process
@@ -63,14 +97,53 @@ func RegisterHandler[TRequest any, TResponse any](handler interfaces.Handler[TRe
 //	    // Send email notification
 //	}
 //	godiator.RegisterSubscriber[UserCreatedEvent](&EmailSubscriber{})
-func RegisterSubscriber[TRequest any](subscriber interfaces.Subscriber[TRequest]) {
-	core.AddSubscriber[TRequest](subscriber)
+func RegisterSubscriber[TRequest any](subscriber interfaces.Subscriber[TRequest], opts ...SubscriberOption) {
+	options := newSubscriberOptions(opts...)
+	filter := subscriberFilter[TRequest](options)
+	registered := subscriber
+	if options.decorates() {
+		handle := func(request TRequest, params ...any) error {
+			subscriber.Handle(request, params...)
+			return nil
+		}
+		registered = decorateSubscriber(handle, subscriber, options)
+	} else if options.name != "" {
+		registered = &renamedSubscriber[TRequest]{subscriber: subscriber, name: options.name}
+	}
+	core.AddSubscriberWithPriority[TRequest](options.priority, filterSubscriber(registered, filter))
+	core.SetPublisher(Publish[TRequest])
+	addSubscriberCloser[TRequest](subscriber)
+}
+
+// RegisterFallibleSubscriber registers a subscriber reporting failures with an error. Failures
+// are retried per WithRetry and, once retries are exhausted, sent to the WithDeadLetterSink
+// sink, or logged when there is none.
+//
+// Type parameters:
+//   - TRequest: The request type that the subscriber will process
+//
+// Example:
+//
+//	sink := deadletter.NewMemorySink()
+//	godiator.RegisterFallibleSubscriber[OrderCreated](&ProjectionSubscriber{},
+//	    godiator.WithRetry(godiator.RetryPolicy{MaxAttempts: 3, Backoff: godiator.ExponentialBackoff(time.Second, time.Minute)}),
+//	    godiator.WithDeadLetterSink(sink))
+func RegisterFallibleSubscriber[TRequest any](subscriber interfaces.FallibleSubscriber[TRequest], opts ...SubscriberOption) {
+	options := newSubscriberOptions(opts...)
+	filter := subscriberFilter[TRequest](options)
+	core.AddSubscriberWithPriority[TRequest](options.priority, filterSubscriber(decorateSubscriber(subscriber.Handle, subscriber, options), filter))
+	core.SetPublisher(Publish[TRequest])
+	addSubscriberCloser[TRequest](subscriber)
 }
 
 // RegisterPipeline registers a pipeline that will be executed before handlers.
-// Pipelines are executed in reverse order of registration (last registered, first executed).
+// Pipelines are executed in order of registration (first registered, first executed).
+// Options name the pipeline and control its position regardless of registration order.
 // Use pipelines for cross-cutting concerns like logging, validation, or authentication.
 //
+// Returns:
+//   - error: An error if the name is already registered or a referenced pipeline is not registered
+//
 // Example:
 //
 //	type LoggingPipeline struct {
@@ -80,9 +153,149 @@ func RegisterSubscriber[TRequest any](subscriber interfaces.Subscriber[TRequest]
 //	    log.Printf("Request: %v", request)
 //	    return p.Next().Handle(request, params...)
 //	}
-//	godiator.RegisterPipeline(&LoggingPipeline{})
-func RegisterPipeline(pipeline interfaces.Pipeline) {
-	core.AddPipeline(pipeline)
+//	godiator.RegisterPipeline(&LoggingPipeline{}, godiator.WithPipelineName("logging"), godiator.WithPipelineOrder(-10))
+func RegisterPipeline(pipeline interfaces.Pipeline, opts ...PipelineOption) error {
+	return insertPipeline(pipeline, opts...)
+}
+
+// RegisterTypedPipeline registers a pipeline for a specific request and response type pair.
+// It is spliced into the chain alongside pipelines registered with RegisterPipeline, accepts
+// the same options, and is skipped for other request types.
+//
+// Type parameters:
+//   - TRequest: The request type that the pipeline will intercept
+//   - TResponse: The response type returned by the handler
+//
+// Returns:
+//   - error: An error if the name is already registered or a referenced pipeline is not registered
+//
+// Example:
+//
+//	godiator.RegisterTypedPipeline[GetUserRequest, GetUserResponse](&MaskEmailPipeline{})
+func RegisterTypedPipeline[TRequest any, TResponse any](pipeline interfaces.TypedPipeline[TRequest, TResponse], opts ...PipelineOption) error {
+	return insertPipeline(&typedPipeline[TRequest, TResponse]{pipeline: pipeline}, opts...)
+}
+
+// RegisterPipelineFor registers a pipeline for every request implementing an interface.
+// It is spliced into the chain alongside pipelines registered with RegisterPipeline, accepts
+// the same options, and is skipped for requests that do not implement the interface.
+//
+// Type parameters:
+//   - TInterface: The interface that intercepted requests implement
+//
+// Returns:
+//   - error: An error if the name is already registered or a referenced pipeline is not registered
+//
+// Example:
+//
+//	godiator.RegisterPipelineFor[Audited](&AuditPipeline{})
+func RegisterPipelineFor[TInterface any](pipeline interfaces.TypedPipelineFor[TInterface], opts ...PipelineOption) error {
+	return insertPipeline(&typedPipelineFor[TInterface]{pipeline: pipeline}, opts...)
+}
+
+// RegisterNotificationPipeline registers a pipeline wrapping every subscriber invocation of Publish,
+// for all event types. Notification pipelines run inside the goroutine of the subscriber, in order
+// of registration, before the pipelines registered for the event type.
+//
+// Parameters:
+//   - pipeline: The notification pipeline to register
+//
+// Example:
+//
+//	godiator.RegisterNotificationPipeline(pipeline.NewRecovery(nil))
+func RegisterNotificationPipeline(pipeline interfaces.NotificationPipeline) {
+	core.AddNotificationPipeline(pipeline)
+}
+
+// RegisterNotificationPipelineFor registers a pipeline wrapping the subscriber invocations of
+// Publish for a specific event type. It runs after the global notification pipelines.
+//
+// Type parameters:
+//   - TRequest: The event type whose subscribers are wrapped
+//
+// Parameters:
+//   - pipeline: The notification pipeline to register
+//
+// Example:
+//
+//	godiator.RegisterNotificationPipelineFor[OrderPlacedEvent](&TenantPipeline{})
+func RegisterNotificationPipelineFor[TRequest any](pipeline interfaces.NotificationPipeline) {
+	core.AddNotificationPipelineFor[TRequest](pipeline)
+}
+
+// RegisterValidator registers a validator for a specific request type.
+// Multiple validators can be registered for the same request type. Validators are
+// evaluated by pipeline.Validation, which must be registered with RegisterPipeline.
+//
+// Type parameters:
+//   - TRequest: The request type that the validator will check
+//
+// Example:
+//
+//	godiator.RegisterValidator[CreateUserRequest](&CreateUserValidator{})
+//	godiator.RegisterPipeline(pipeline.NewValidation())
+func RegisterValidator[TRequest any](validator interfaces.Validator[TRequest]) {
+	core.AddValidator[TRequest](validator)
+}
+
+// RegisterPolicy registers an authorization policy for a specific request type.
+// Multiple policies can be registered for the same request type; all of them must pass.
+// Policies are evaluated by pipeline.Authorization, which must be registered with RegisterPipeline.
+//
+// Type parameters:
+//   - TRequest: The request type that the policy will authorize
+//
+// Example:
+//
+//	godiator.RegisterPolicy(func(ctx context.Context, principal interfaces.Principal, req DeleteOrderCommand) error {
+//	    return checkOrderOwner(ctx, principal, req.OrderID)
+//	})
+//	godiator.RegisterPipeline(pipeline.NewAuthorization())
+func RegisterPolicy[TRequest any](policy interfaces.Policy[TRequest]) {
+	core.AddPolicy[TRequest](policy)
+}
+
+// RegisterPreProcessor registers a pre-processor for a specific request type.
+// Pre-processors run in registration order, after the pipelines and right before the handler.
+//
+// Type parameters:
+//   - TRequest: The request type that the pre-processor will process
+//
+// Example:
+//
+//	godiator.RegisterPreProcessor[CreateUserRequest](&NormalizeEmailPreProcessor{})
+func RegisterPreProcessor[TRequest any](processor interfaces.PreProcessor[TRequest]) {
+	core.AddPreProcessor[TRequest](processor)
+}
+
+// RegisterPostProcessor registers a post-processor for a specific request and response type pair.
+// Post-processors run in registration order, right after the handler succeeds.
+//
+// Type parameters:
+//   - TRequest: The request type that the post-processor will process
+//   - TResponse: The response type returned by the handler
+//
+// Example:
+//
+//	godiator.RegisterPostProcessor[CreateUserRequest, CreateUserResponse](&AuditPostProcessor{})
+func RegisterPostProcessor[TRequest any, TResponse any](processor interfaces.PostProcessor[TRequest, TResponse]) {
+	core.AddPostProcessor[TRequest, TResponse](processor)
+}
+
+// RegisterExceptionHandler registers an exception handler for a specific request, response and error type.
+// When Send fails with an error matching TErr, exception handlers are tried in registration order and
+// the first one handling the error provides the response returned instead.
+//
+// Type parameters:
+//   - TRequest: The request type that the exception handler will process
+//   - TResponse: The response type returned by the handler
+//   - TErr: The error type that the exception handler will handle
+//
+// Example:
+//
+//	godiator.RegisterExceptionHandler[GetUserRequest, GetUserResponse, *NotFoundError](&NotFoundExceptionHandler{})
+func RegisterExceptionHandler[TRequest any, TResponse any, TErr error](handler interfaces.ExceptionHandler[TRequest, TResponse, TErr]) {
+	core.AddExceptionHandler[TRequest, TResponse, TErr](handler)
 }
 
 // UnregisterHandler removes the registered handler for the specified request type.
@@ -96,6 +309,22 @@ func RegisterPipeline(pipeline interfaces.Pipeline) {
 //	godiator.UnregisterHandler[GetUserRequest]()
 func UnregisterHandler[TRequest any]() {
 	core.RemoveHandler[TRequest]()
+	setBulkhead[TRequest](nil)
+	setHandlerCloser[TRequest](nil)
+	notifyHandlerUnregistered[TRequest]()
+}
+
+// UnregisterNotificationPipelines removes the notification pipelines registered for the specified
+// event type. Global notification pipelines are kept.
+//
+// Type parameters:
+//   - TRequest: The event type whose notification pipelines should be removed
+//
+// Example:
+//
+//	godiator.UnregisterNotificationPipelines[OrderPlacedEvent]()
+func UnregisterNotificationPipelines[TRequest any]() {
+	core.RemoveNotificationPipelines[TRequest]()
 }
 
 // UnregisterSubscriber removes all registered subscribers for the specified request type.
@@ -109,11 +338,52 @@ func UnregisterHandler[TRequest any]() {
 //	godiator.UnregisterSubscriber[UserCreatedEvent]()
 func UnregisterSubscriber[TRequest any]() {
 	core.RemoveSubscriber[TRequest]()
+	removeSubscriberClosers[TRequest]()
+	removeRedrivers[TRequest]()
+}
+
+// UnregisterValidators removes all registered validators for the specified request type.
+//
+// Type parameters:
+//   - TRequest: The request type whose validators should be removed
+//
+// Example:
+//
+//	godiator.UnregisterValidators[CreateUserRequest]()
+func UnregisterValidators[TRequest any]() {
+	core.RemoveValidators[TRequest]()
+}
+
+// UnregisterPolicies removes all registered authorization policies for the specified request type.
+//
+// Type parameters:
+//   - TRequest: The request type whose policies should be removed
+//
+// Example:
+//
+//	godiator.UnregisterPolicies[DeleteOrderCommand]()
+func UnregisterPolicies[TRequest any]() {
+	core.RemovePolicies[TRequest]()
+}
+
+// UnregisterProcessors removes all registered pre-processors, post-processors and
+// exception handlers for the specified request type.
+//
+// Type parameters:
+//   - TRequest: The request type whose processors should be removed
+//
+// Example:
+//
+//	godiator.UnregisterProcessors[CreateUserRequest]()
+func UnregisterProcessors[TRequest any]() {
+	core.RemoveProcessors[TRequest]()
 }
 
 // Send dispatches a request to its registered handler and returns the response.
-// If pipelines are registered, they will be executed in reverse order of registration
-// before the handler is invoked.
+// If pipelines are registered, they will be executed in their effective order
+// (see Pipelines) before the handler is invoked. Pre-processors and post-processors registered for the
+// request type run right before and after the handler, and exception handlers may turn
+// an error returned by the chain into a fallback response.
 //
 // Type parameters:
 //   - TRequest: The request type to send
@@ -125,7 +395,7 @@ func UnregisterSubscriber[TRequest any]() {
 //
 // Returns:
 //   - TResponse: The response from the handler
-//   - error: An error if the handler is not found or if processing fails
+//   - error: An error if the handler is not found, if processing fails, or ErrShuttingDown once Shutdown has been called
 //
 // Example:
 //
@@ -134,7 +404,32 @@ func UnregisterSubscriber[TRequest any]() {
 //	    log.Fatal(err)
 //	}
 //	fmt.Println(response.Name)
-func Send[TRequest any, TResponse any](request TRequest, params ...any) (TResponse, error) {
+func Send[TRequest any, TResponse any](request TRequest, params ...any) (response TResponse, err error) {
+	requestType := reflect.TypeFor[TRequest]().String()
+	id, params, ok := inFlight.beginCall(AbandonedWork{Kind: "send", RequestType: requestType}, params)
+	if !ok {
+		return response, ErrShuttingDown
+	}
+	defer inFlight.end(id)
+
+	if metrics := core.GetMetrics(); metrics != nil {
+		metrics.SendStarted(requestType)
+		defer observeSend(metrics, requestType, time.Now(), &err)
+	}
+	if tracer := core.GetTracer(); tracer != nil {
+		var span interfaces.Span
+		span, params = startSpan(tracer, "send "+requestType, interfaces.SpanConfig{
+			Attributes: map[string]string{"godiator.request_type": requestType},
+		}, params)
+		defer finishSpan(span, &err)
+	}
+	notifyObservers(func(o interfaces.Observer) { o.BeforeSend(request, params...) })
+	defer notifyAfterSend(request, &response, &err, time.Now())
+	return send[TRequest, TResponse](request, params...)
+}
+
+// send dispatches the request through the pipelines to its handler.
+func send[TRequest any, TResponse any](request TRequest, params ...any) (TResponse, error) {
 	handler, ok := core.GetHandler[TRequest, TResponse]()
 	if !ok {
 		var emptyResponse TResponse
@@ -142,8 +437,13 @@ func Send[TRequest any, TResponse any](request TRequest, params ...any) (TRespon
 	}
 
 	messagePipelines := core.GetPipelines()
-	executionPipeline := &executionPipeline{
-		wrapperFunc: handler.Handle,
+	var handlerPipeline interfaces.Pipeline = &executionPipeline{
+		wrapperFunc: func(request any, params ...any) (any, error) {
+			return handleWithProcessors[TRequest, TResponse](handler, request.(TRequest), params...)
+		},
+	}
+	if tracer := core.GetTracer(); tracer != nil {
+		messagePipelines, handlerPipeline = tracedChain(tracer, reflect.TypeFor[TRequest]().String(), handlerPipeline)
 	}
 
 	var response any
@@ -153,7 +453,7 @@ func Send[TRequest any, TResponse any](request TRequest, params ...any) (TRespon
 		var firstPipeline interfaces.Pipeline
 		for _, pipeline := range slices.Backward(messagePipelines) {
 			if firstPipeline == nil {
-				pipeline.SetNext(executionPipeline)
+				pipeline.SetNext(handlerPipeline)
 				firstPipeline = pipeline
 			} else {
 				pipeline.SetNext(firstPipeline)
@@ -161,15 +461,28 @@ func Send[TRequest any, TResponse any](request TRequest, params ...any) (TRespon
 			}
 		}
 		response, err = firstPipeline.Handle(request, params...)
-		return response.(TResponse), err
 	} else {
-		response, err := executionPipeline.Handle(request, params...)
-		return response.(TResponse), err
+		response, err = handlerPipeline.Handle(request, params...)
+	}
+
+	// Pipelines that reject a request may return a nil response, but any other response must
+	// match the response type
+	typedResponse, ok := response.(TResponse)
+	if !ok && response != nil && err == nil {
+		err = fmt.Errorf(`response of type %T returned for "%s" is not a %s`, response, reflect.TypeOf(request).String(), reflect.TypeFor[TResponse]())
 	}
+	if err != nil {
+		return handleException(request, typedResponse, err, params...)
+	}
+	return typedResponse, nil
 }
 
 // Publish dispatches a request to all registered subscribers asynchronously.
-// Each subscriber is executed in a separate goroutine, making this a fire-and-forget operation.
+// Each subscriber is executed in a separate goroutine, or by the workers of the event queue
+// enabled with UseEventQueue, making this a fire-and-forget operation. Events implementing
+// Partitioned are delivered to each subscriber in order per key, and the subscribers of events
+// set to DispatchSequential run one after the other in order of priority.
+// Cache invalidations declared with InvalidateCacheOn run synchronously before the subscribers.
 // If no subscribers are registered for the request type, a message is printed to stdout.
 //
 // Type parameters:
@@ -179,16 +492,77 @@ func Send[TRequest any, TResponse any](request TRequest, params ...any) (TRespon
 //   - request: The request object to publish to all subscribers
 //   - params: Optional additional parameters passed to each subscriber
 //
+// Returns:
+//   - error: ErrShuttingDown once Shutdown has been called, or when the event queue is enabled,
+//     ErrEventQueueFull or the context error depending on its overflow policy. Without the event
+//     queue, the context error if the lane of a Partitioned event stays full
+//
 // Example:
 //
 //	godiator.Publish[UserCreatedEvent](UserCreatedEvent{UserID: 123, Email: "user@example.com"})
-func Publish[TRequest any](request TRequest, params ...any) {
+func Publish[TRequest any](request TRequest, params ...any) error {
+	eventType := reflect.TypeFor[TRequest]().String()
+	id, params, ok := inFlight.beginCall(AbandonedWork{Kind: "publish", RequestType: eventType}, params)
+	if !ok {
+		return ErrShuttingDown
+	}
+	defer inFlight.end(id)
+
+	if tracer := core.GetTracer(); tracer != nil {
+		var span interfaces.Span
+		span, params = startSpan(tracer, "publish "+eventType, interfaces.SpanConfig{
+			Attributes: map[string]string{"godiator.event_type": eventType},
+		}, params)
+		defer span.End()
+	}
+	notifyObservers(func(o interfaces.Observer) { o.BeforePublish(request, params...) })
+
+	for _, hook := range core.GetPublishHooks[TRequest]() {
+		hook(request)
+	}
+
 	subscribers := core.GetSubscribers[TRequest]()
-	if len(subscribers) > 0 {
-		for _, subscriber := range subscribers {
-			go subscriber.Handle(request, params...)
-		}
-	} else {
+	if len(subscribers) == 0 {
 		fmt.Printf(`handler not found for "%s" \n`, reflect.TypeOf(request).String())
+		return nil
+	}
+
+	queue := currentEventQueue()
+	partitioned, isPartitioned := any(request).(Partitioned)
+	subscriberParams := params
+	if queue != nil {
+		subscriberParams = markQueued(subscriberParams)
+	}
+	if isPartitioned {
+		subscriberParams = markPartitioned(subscriberParams)
+	}
+	invocations := make([]queuedInvocation, 0, len(subscribers))
+	for _, subscriber := range subscribers {
+		if !subscriber.Accepts(request) {
+			continue
+		}
+		name := subscriber.Name()
+		invocations = append(invocations, queuedInvocation{
+			eventType:  eventType,
+			subscriber: name,
+			workIDs:    []uint64{inFlight.add(AbandonedWork{Kind: "subscriber", RequestType: eventType, Subscriber: name})},
+			run:        func() { handleSubscriber(&subscriber, request, subscriberParams...) },
+		})
+	}
+	if len(invocations) == 0 {
+		return nil
+	}
+	if dispatchModeOf[TRequest]() == DispatchSequential {
+		invocations = []queuedInvocation{sequence(invocations)}
+	}
+	if isPartitioned {
+		key := partitioned.PartitionKey()
+		for i := range invocations {
+			invocations[i].lane = laneOf(invocations[i], key)
+		}
+	}
+	if queue != nil {
+		return queue.enqueue(params, invocations)
 	}
+	return runUnqueued(params, invocations)
 }
@@ -0,0 +1,7 @@
+// Package testhooks gives the tests of the module access to process-wide state of the mediator
+// that applications cannot reset.
+package testhooks
+
+// ResetShutdown reopens the mediator after godiator.Shutdown, so that a test can shut it down
+// again. It must not be called while Shutdown is in progress. Set by the godiator package.
+var ResetShutdown func()
@@ -0,0 +1,44 @@
+package godiator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+// SetMetrics sets the recorder that Send and Publish report their activity to.
+// Passing nil disables metrics, which is the default.
+//
+// Parameters:
+//   - metrics: The metrics recorder
+//
+// Example:
+//
+//	registry := metrics.NewRegistry()
+//	godiator.SetMetrics(registry)
+//	http.Handle("/metrics", registry)
+func SetMetrics(metrics interfaces.Metrics) {
+	core.SetMetrics(metrics)
+}
+
+// observeSend reports the completion of a request. It is deferred by Send, so that a panic
+// is reported as an error before it is propagated.
+func observeSend(metrics interfaces.Metrics, requestType string, start time.Time, err *error) {
+	if r := recover(); r != nil {
+		metrics.SendFinished(requestType, time.Since(start), fmt.Errorf("panic: %v", r))
+		panic(r)
+	}
+	metrics.SendFinished(requestType, time.Since(start), *err)
+}
+
+// observeSubscriber reports the completion of a subscriber with the error it failed with,
+// reporting a panic as an error before it is propagated.
+func observeSubscriber(metrics interfaces.Metrics, eventType string, subscriber string, start time.Time, err *error) {
+	if r := recover(); r != nil {
+		metrics.SubscriberFinished(eventType, subscriber, time.Since(start), fmt.Errorf("panic: %v", r))
+		panic(r)
+	}
+	metrics.SubscriberFinished(eventType, subscriber, time.Since(start), *err)
+}
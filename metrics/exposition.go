@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"expvar"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the content type of the Prometheus text exposition format written by WritePrometheus.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WritePrometheus writes the collected metrics in the Prometheus text exposition format.
+//
+// Parameters:
+//   - w: The writer the metrics are written to
+//
+// Returns:
+//   - error: An error if writing fails
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	snapshot := r.Snapshot()
+	buf := bufio.NewWriter(w)
+
+	requests := make([]labeledStats, len(snapshot.Requests))
+	for i, s := range snapshot.Requests {
+		requests[i] = labeledStats{labels: fmt.Sprintf(`request_type="%s"`, labelEscaper.Replace(s.RequestType)), stats: s.Stats}
+	}
+	writeFamily(buf, "godiator_requests", "requests sent", requests)
+
+	subscribers := make([]labeledStats, len(snapshot.Subscribers))
+	for i, s := range snapshot.Subscribers {
+		labels := fmt.Sprintf(`event_type="%s",subscriber="%s"`, labelEscaper.Replace(s.EventType), labelEscaper.Replace(s.Subscriber))
+		subscribers[i] = labeledStats{labels: labels, stats: s.Stats}
+	}
+	writeFamily(buf, "godiator_subscriber", "subscriber executions", subscribers)
+
+	queue := snapshot.EventQueue
+	fmt.Fprintf(buf, "# HELP godiator_event_queue_depth Number of subscriber invocations in the event queue.\n# TYPE godiator_event_queue_depth gauge\n")
+	fmt.Fprintf(buf, "godiator_event_queue_depth %d\n", queue.Depth)
+	fmt.Fprintf(buf, "# HELP godiator_event_queue_capacity Capacity of the event queue.\n# TYPE godiator_event_queue_capacity gauge\n")
+	fmt.Fprintf(buf, "godiator_event_queue_capacity %d\n", queue.Capacity)
+	fmt.Fprintf(buf, "# HELP godiator_event_queue_dropped_total Total number of subscriber invocations dropped by the event queue.\n# TYPE godiator_event_queue_dropped_total counter\n")
+	for _, eventType := range slices.Sorted(maps.Keys(queue.Dropped)) {
+		fmt.Fprintf(buf, "godiator_event_queue_dropped_total{event_type=\"%s\"} %d\n", labelEscaper.Replace(eventType), queue.Dropped[eventType])
+	}
+
+	return buf.Flush()
+}
+
+// ServeHTTP serves the collected metrics in the Prometheus text exposition format,
+// so that the registry can be mounted as a scrape endpoint. The metrics are rendered before
+// anything is written, so a rendering error is answered with a 500 instead of a truncated body.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var body bytes.Buffer
+	if err := r.WritePrometheus(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(body.Len()))
+	if _, err := body.WriteTo(w); err != nil {
+		// The scraper went away; there is nobody left to report the error to
+		slog.Debug("writing metrics response", "error", err)
+	}
+}
+
+// PublishExpvar publishes the snapshot of the registry to expvar under the name, so that it
+// is served at /debug/vars. Like expvar.Publish, it panics if the name is already in use.
+//
+// Parameters:
+//   - name: The name of the expvar variable
+func (r *Registry) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return r.Snapshot()
+	}))
+}
+
+type labeledStats struct {
+	labels string
+	stats  Stats
+}
+
+// writeFamily writes the counters, gauge and histogram of a group of series. Errors are
+// reported by the final flush of the buffered writer.
+func writeFamily(w *bufio.Writer, prefix string, help string, series []labeledStats) {
+	fmt.Fprintf(w, "# HELP %s_total Total number of %s.\n# TYPE %s_total counter\n", prefix, help, prefix)
+	for _, s := range series {
+		fmt.Fprintf(w, "%s_total{%s} %d\n", prefix, s.labels, s.stats.Calls)
+	}
+	fmt.Fprintf(w, "# HELP %s_errors_total Total number of failed %s.\n# TYPE %s_errors_total counter\n", prefix, help, prefix)
+	for _, s := range series {
+		fmt.Fprintf(w, "%s_errors_total{%s} %d\n", prefix, s.labels, s.stats.Errors)
+	}
+	fmt.Fprintf(w, "# HELP %s_in_flight Number of %s in flight.\n# TYPE %s_in_flight gauge\n", prefix, help, prefix)
+	for _, s := range series {
+		fmt.Fprintf(w, "%s_in_flight{%s} %d\n", prefix, s.labels, s.stats.InFlight)
+	}
+	fmt.Fprintf(w, "# HELP %s_duration_seconds Latency of %s.\n# TYPE %s_duration_seconds histogram\n", prefix, help, prefix)
+	for _, s := range series {
+		for _, bucket := range s.stats.Latency.Buckets {
+			le := strconv.FormatFloat(bucket.UpperBound, 'g', -1, 64)
+			fmt.Fprintf(w, "%s_duration_seconds_bucket{%s,le=\"%s\"} %d\n", prefix, s.labels, le, bucket.Count)
+		}
+		fmt.Fprintf(w, "%s_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", prefix, s.labels, s.stats.Latency.Count)
+		fmt.Fprintf(w, "%s_duration_seconds_sum{%s} %s\n", prefix, s.labels, strconv.FormatFloat(s.stats.Latency.Sum, 'g', -1, 64))
+		fmt.Fprintf(w, "%s_duration_seconds_count{%s} %d\n", prefix, s.labels, s.stats.Latency.Count)
+	}
+}
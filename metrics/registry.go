@@ -0,0 +1,273 @@
+// Package metrics provides an in-process implementation of the godiator Metrics interface.
+// Collected metrics can be rendered in the Prometheus text exposition format or published
+// to expvar.
+package metrics
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var (
+	_ interfaces.Metrics           = (*Registry)(nil)
+	_ interfaces.EventQueueMetrics = (*Registry)(nil)
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the latency histogram buckets.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Bucket is a cumulative histogram bucket.
+type Bucket struct {
+	// UpperBound is the upper bound of the bucket in seconds.
+	UpperBound float64
+	// Count is the number of observations less than or equal to the upper bound.
+	Count uint64
+}
+
+// Histogram is a latency distribution.
+type Histogram struct {
+	Buckets []Bucket
+	// Sum is the sum of all observations in seconds.
+	Sum float64
+	// Count is the number of observations.
+	Count uint64
+}
+
+// Stats are the metrics of a request type or subscriber.
+type Stats struct {
+	Calls    uint64
+	Errors   uint64
+	InFlight int64
+	Latency  Histogram
+}
+
+// RequestStats are the metrics of a request type sent with godiator.Send.
+type RequestStats struct {
+	RequestType string
+	Stats
+}
+
+// SubscriberStats are the metrics of a subscriber of an event type published with godiator.Publish.
+type SubscriberStats struct {
+	EventType  string
+	Subscriber string
+	Stats
+}
+
+// EventQueueStats are the metrics of the event queue enabled with godiator.UseEventQueue.
+type EventQueueStats struct {
+	Depth    int
+	Capacity int
+	// Dropped is the number of dropped subscriber invocations per event type.
+	Dropped map[string]uint64
+}
+
+// Snapshot is a copy of the metrics collected by a Registry, sorted by label.
+type Snapshot struct {
+	Requests    []RequestStats
+	Subscribers []SubscriberStats
+	EventQueue  EventQueueStats
+}
+
+// Option configures a Registry.
+type Option func(*Registry)
+
+// WithBuckets sets the upper bounds, in seconds, of the latency histogram buckets.
+// Defaults to DefaultBuckets.
+func WithBuckets(buckets ...float64) Option {
+	return func(r *Registry) {
+		if len(buckets) > 0 {
+			r.buckets = slices.Sorted(slices.Values(buckets))
+		}
+	}
+}
+
+type subscriberKey struct {
+	eventType  string
+	subscriber string
+}
+
+// series accumulates the metrics of a single request type or subscriber.
+type series struct {
+	calls    uint64
+	errors   uint64
+	inFlight int64
+	counts   []uint64
+	sum      float64
+	count    uint64
+}
+
+func (s *series) observe(buckets []float64, duration time.Duration, err error) {
+	s.inFlight--
+	if err != nil {
+		s.errors++
+	}
+	seconds := duration.Seconds()
+	s.sum += seconds
+	s.count++
+	if i, _ := slices.BinarySearch(buckets, seconds); i < len(buckets) {
+		s.counts[i]++
+	}
+}
+
+func (s *series) stats(buckets []float64) Stats {
+	stats := Stats{
+		Calls:    s.calls,
+		Errors:   s.errors,
+		InFlight: s.inFlight,
+		Latency:  Histogram{Buckets: make([]Bucket, len(buckets)), Sum: s.sum, Count: s.count},
+	}
+	var cumulative uint64
+	for i, bound := range buckets {
+		cumulative += s.counts[i]
+		stats.Latency.Buckets[i] = Bucket{UpperBound: bound, Count: cumulative}
+	}
+	return stats
+}
+
+// Registry collects call counts, error counts, in-flight gauges and latency histograms per
+// request type and per subscriber. Register it with godiator.SetMetrics.
+type Registry struct {
+	buckets []float64
+
+	mu          sync.Mutex
+	requests    map[string]*series
+	subscribers map[subscriberKey]*series
+	queue       EventQueueStats
+}
+
+// NewRegistry creates an empty Registry.
+//
+// Parameters:
+//   - opts: Options changing the histogram buckets
+//
+// Returns:
+//   - *Registry: The created registry
+func NewRegistry(opts ...Option) *Registry {
+	r := &Registry{
+		buckets:     DefaultBuckets,
+		requests:    make(map[string]*series),
+		subscribers: make(map[subscriberKey]*series),
+		queue:       EventQueueStats{Dropped: make(map[string]uint64)},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// SendStarted records a request being sent.
+func (r *Registry) SendStarted(requestType string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.requests[requestType]
+	if s == nil {
+		s = r.newSeries()
+		r.requests[requestType] = s
+	}
+	s.calls++
+	s.inFlight++
+}
+
+// SendFinished records the completion of a request.
+func (r *Registry) SendFinished(requestType string, duration time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s := r.requests[requestType]; s != nil {
+		s.observe(r.buckets, duration, err)
+	}
+}
+
+// SubscriberStarted records a subscriber starting to handle an event.
+func (r *Registry) SubscriberStarted(eventType string, subscriber string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := subscriberKey{eventType: eventType, subscriber: subscriber}
+	s := r.subscribers[key]
+	if s == nil {
+		s = r.newSeries()
+		r.subscribers[key] = s
+	}
+	s.calls++
+	s.inFlight++
+}
+
+// SubscriberFinished records the completion of a subscriber.
+func (r *Registry) SubscriberFinished(eventType string, subscriber string, duration time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s := r.subscribers[subscriberKey{eventType: eventType, subscriber: subscriber}]; s != nil {
+		s.observe(r.buckets, duration, err)
+	}
+}
+
+// EventQueueDepth records the depth of the event queue.
+func (r *Registry) EventQueueDepth(depth int, capacity int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.queue.Depth = depth
+	r.queue.Capacity = capacity
+}
+
+// EventDropped records a subscriber invocation dropped by the event queue.
+func (r *Registry) EventDropped(eventType string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.queue.Dropped[eventType]++
+}
+
+// Snapshot returns a copy of the collected metrics.
+//
+// Returns:
+//   - Snapshot: The collected metrics, sorted by request type and by event type and subscriber
+func (r *Registry) Snapshot() Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := Snapshot{
+		Requests:    make([]RequestStats, 0, len(r.requests)),
+		Subscribers: make([]SubscriberStats, 0, len(r.subscribers)),
+		EventQueue:  EventQueueStats{Depth: r.queue.Depth, Capacity: r.queue.Capacity, Dropped: maps.Clone(r.queue.Dropped)},
+	}
+	for requestType, s := range r.requests {
+		snapshot.Requests = append(snapshot.Requests, RequestStats{RequestType: requestType, Stats: s.stats(r.buckets)})
+	}
+	for key, s := range r.subscribers {
+		snapshot.Subscribers = append(snapshot.Subscribers, SubscriberStats{EventType: key.eventType, Subscriber: key.subscriber, Stats: s.stats(r.buckets)})
+	}
+	slices.SortFunc(snapshot.Requests, func(a, b RequestStats) int {
+		return cmp.Compare(a.RequestType, b.RequestType)
+	})
+	slices.SortFunc(snapshot.Subscribers, func(a, b SubscriberStats) int {
+		if c := cmp.Compare(a.EventType, b.EventType); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Subscriber, b.Subscriber)
+	})
+	return snapshot
+}
+
+// Reset discards all collected metrics.
+func (r *Registry) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests = make(map[string]*series)
+	r.subscribers = make(map[subscriberKey]*series)
+	r.queue = EventQueueStats{Dropped: make(map[string]uint64)}
+}
+
+func (r *Registry) newSeries() *series {
+	return &series{counts: make([]uint64, len(r.buckets))}
+}
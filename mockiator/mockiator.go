@@ -24,7 +24,7 @@ type mockHandler[TRequest any, TResponse any] struct {
 func (m *mockHandler[TRequest, TResponse]) Handle(request TRequest, params ...any) (TResponse, error) {
 	m.IsCalled = true
 	m.TimesCalled++
-	return m.handlerFunc(request, params)
+	return m.handlerFunc(request, params...)
 }
 
 // OnSend creates and registers a mock handler for the specified request and response types.
@@ -56,7 +56,7 @@ type mockSubscriber[TRequest any] struct {
 func (s *mockSubscriber[TRequest]) Handle(request TRequest, params ...any) {
 	s.IsCalled = true
 	s.TimesCalled++
-	s.handlerFunc(request, params)
+	s.handlerFunc(request, params...)
 }
 
 // OnPublish creates and registers a mock subscriber for the specified request type.
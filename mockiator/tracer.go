@@ -0,0 +1,110 @@
+package mockiator
+
+import (
+	"context"
+	"sync"
+
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var _ interfaces.Tracer = (*SpanRecorder)(nil)
+
+type spanKey struct{}
+
+// RecordedSpan is a span started by a SpanRecorder.
+type RecordedSpan struct {
+	// ID identifies the span within its recorder, starting at 1.
+	ID int
+	// ParentID is the ID of the parent span, 0 for root spans.
+	ParentID int
+	// LinkIDs are the IDs of the spans the span is linked to.
+	LinkIDs    []int
+	Name       string
+	Attributes map[string]string
+	// Err is the error the span was marked as failed with.
+	Err   error
+	Ended bool
+}
+
+// recordedSpan is the live Span handed out by a SpanRecorder.
+type recordedSpan struct {
+	recorder *SpanRecorder
+	index    int
+}
+
+func (s *recordedSpan) SetError(err error) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	s.recorder.spans[s.index].Err = err
+}
+
+func (s *recordedSpan) End() {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	s.recorder.spans[s.index].Ended = true
+}
+
+// SpanRecorder is an in-memory Tracer recording every span, to assert on traces in tests.
+//
+// Example:
+//
+//	recorder := mockiator.NewSpanRecorder()
+//	godiator.SetTracer(recorder)
+//	godiator.Send[GetUserRequest, GetUserResponse](GetUserRequest{ID: 1})
+//	spans := recorder.Spans()
+type SpanRecorder struct {
+	mu    sync.Mutex
+	spans []RecordedSpan
+}
+
+// NewSpanRecorder creates an empty SpanRecorder.
+//
+// Returns:
+//   - *SpanRecorder: The created recorder.
+func NewSpanRecorder() *SpanRecorder {
+	return &SpanRecorder{}
+}
+
+// Start records a new span as a child of the span found in the context, unless it is a root span.
+//
+// Parameters:
+//   - ctx: The context of the parent span.
+//   - name: The name of the span.
+//   - config: The attributes and links of the span.
+//
+// Returns:
+//   - context.Context: The context carrying the new span.
+//   - interfaces.Span: The new span.
+func (r *SpanRecorder) Start(ctx context.Context, name string, config interfaces.SpanConfig) (context.Context, interfaces.Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	span := RecordedSpan{ID: len(r.spans) + 1, Name: name, Attributes: config.Attributes}
+	if parent, ok := ctx.Value(spanKey{}).(*recordedSpan); ok && !config.Root {
+		span.ParentID = parent.index + 1
+	}
+	for _, link := range config.Links {
+		if linked, ok := link.Value(spanKey{}).(*recordedSpan); ok {
+			span.LinkIDs = append(span.LinkIDs, linked.index+1)
+		}
+	}
+	r.spans = append(r.spans, span)
+
+	live := &recordedSpan{recorder: r, index: len(r.spans) - 1}
+	return context.WithValue(ctx, spanKey{}, live), live
+}
+
+// Spans returns a copy of the recorded spans in the order they were started.
+//
+// Returns:
+//   - []RecordedSpan: The recorded spans.
+func (r *SpanRecorder) Spans() []RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	spans := make([]RecordedSpan, len(r.spans))
+	copy(spans, r.spans)
+	return spans
+}
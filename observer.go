@@ -0,0 +1,123 @@
+package godiator
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var _ interfaces.Observer = BaseObserver{}
+
+// BaseObserver implements every method of the Observer interface as a no-op.
+// Embed it in observers to implement only the methods of interest.
+type BaseObserver struct{}
+
+// BeforeSend does nothing.
+func (BaseObserver) BeforeSend(request any, params ...any) {}
+
+// AfterSend does nothing.
+func (BaseObserver) AfterSend(request any, response any, err error, duration time.Duration) {}
+
+// BeforePublish does nothing.
+func (BaseObserver) BeforePublish(event any, params ...any) {}
+
+// SubscriberStarted does nothing.
+func (BaseObserver) SubscriberStarted(event any, subscriber string) {}
+
+// SubscriberFinished does nothing.
+func (BaseObserver) SubscriberFinished(event any, subscriber string, duration time.Duration) {}
+
+// SubscriberFailed does nothing.
+func (BaseObserver) SubscriberFailed(event any, subscriber string, err error) {}
+
+// HandlerRegistered does nothing.
+func (BaseObserver) HandlerRegistered(requestType string) {}
+
+// HandlerUnregistered does nothing.
+func (BaseObserver) HandlerUnregistered(requestType string) {}
+
+// RegisterObserver registers an observer notified of sent requests, published events,
+// subscriber executions and handler registrations. Observers are notified synchronously,
+// in registration order, and cannot alter requests or responses.
+//
+// Parameters:
+//   - observer: The observer to register
+//
+// Returns:
+//   - func() bool: Removes this registration of the observer, reporting whether it was still
+//     registered. Unlike UnregisterObserver, it works for observers whose type is not comparable
+//
+// Example:
+//
+//	unregister := godiator.RegisterObserver(&SlowRequestObserver{})
+//	defer unregister()
+func RegisterObserver(observer interfaces.Observer) func() bool {
+	id := core.AddObserver(observer)
+	return func() bool {
+		return core.RemoveObserverByID(id)
+	}
+}
+
+// UnregisterObserver removes an observer registered with RegisterObserver. Observers are
+// compared with ==, so observers whose type is not comparable, such as structs holding a slice
+// or map, are never found; remove them with the function returned by RegisterObserver instead.
+//
+// Parameters:
+//   - observer: The observer to remove
+//
+// Returns:
+//   - bool: Indicates whether the observer was registered
+func UnregisterObserver(observer interfaces.Observer) bool {
+	return core.RemoveObserver(observer)
+}
+
+// notifyObservers calls every registered observer, ignoring their panics.
+func notifyObservers(notify func(observer interfaces.Observer)) {
+	for _, observer := range core.GetObservers() {
+		func() {
+			defer func() { recover() }()
+			notify(observer)
+		}()
+	}
+}
+
+// notifyAfterSend notifies observers of a completed request. It is deferred by Send, so that
+// a panic is reported as an error before it is propagated.
+func notifyAfterSend[TResponse any](request any, response *TResponse, err *error, start time.Time) {
+	if r := recover(); r != nil {
+		panicErr := fmt.Errorf("panic: %v", r)
+		notifyObservers(func(o interfaces.Observer) { o.AfterSend(request, nil, panicErr, time.Since(start)) })
+		panic(r)
+	}
+	notifyObservers(func(o interfaces.Observer) { o.AfterSend(request, *response, *err, time.Since(start)) })
+}
+
+// notifySubscriberDone notifies observers of a completed subscriber, reporting the error it
+// failed with, or a panic before it is propagated, as a failure.
+func notifySubscriberDone(event any, subscriber string, start time.Time, err *error) {
+	if r := recover(); r != nil {
+		panicErr := fmt.Errorf("panic: %v", r)
+		notifyObservers(func(o interfaces.Observer) { o.SubscriberFailed(event, subscriber, panicErr) })
+		panic(r)
+	}
+	if *err != nil {
+		notifyObservers(func(o interfaces.Observer) { o.SubscriberFailed(event, subscriber, *err) })
+		return
+	}
+	notifyObservers(func(o interfaces.Observer) { o.SubscriberFinished(event, subscriber, time.Since(start)) })
+}
+
+// notifyHandlerRegistered notifies observers that a handler was registered for the request type.
+func notifyHandlerRegistered[TRequest any]() {
+	requestType := reflect.TypeFor[TRequest]().String()
+	notifyObservers(func(o interfaces.Observer) { o.HandlerRegistered(requestType) })
+}
+
+// notifyHandlerUnregistered notifies observers that the handler of the request type was unregistered.
+func notifyHandlerUnregistered[TRequest any]() {
+	requestType := reflect.TypeFor[TRequest]().String()
+	notifyObservers(func(o interfaces.Observer) { o.HandlerUnregistered(requestType) })
+}
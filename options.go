@@ -0,0 +1,152 @@
+package godiator
+
+import (
+	"fmt"
+
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+// HandlerOption configures how a handler registered with RegisterHandler is executed.
+type HandlerOption func(*handlerOptions)
+
+type handlerOptions struct {
+	singleflight   bool
+	maxConcurrency int
+	maxQueue       int
+}
+
+// WithSingleflight deduplicates identical concurrent requests so that only one handler
+// execution runs and every waiting caller shares its response and error. Requests are
+// identical when they return the same key from SingleflightKey, or, for requests that do not
+// implement SingleflightKeyer, when their values are equal. Requests whose values are not
+// comparable are never deduplicated. Each caller stops waiting once its own context is done;
+// the shared execution runs with the params of the first caller, detached from its cancellation.
+func WithSingleflight() HandlerOption {
+	return func(o *handlerOptions) {
+		o.singleflight = true
+	}
+}
+
+// WithMaxConcurrency caps the number of concurrent executions of the handler. Requests
+// beyond the cap wait for a free slot in a queue of up to maxQueue requests; once the queue
+// is full, Send returns a *BulkheadFullError. Use HandlerStats to inspect the current load.
+func WithMaxConcurrency(maxConcurrency int, maxQueue int) HandlerOption {
+	return func(o *handlerOptions) {
+		if maxConcurrency > 0 {
+			o.maxConcurrency = maxConcurrency
+			o.maxQueue = max(maxQueue, 0)
+		}
+	}
+}
+
+// decorateHandler wraps the handler according to the registration options.
+func decorateHandler[TRequest any, TResponse any](handler interfaces.Handler[TRequest, TResponse], opts ...HandlerOption) interfaces.Handler[TRequest, TResponse] {
+	var options handlerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.maxConcurrency > 0 {
+		bulkhead := newBulkheadHandler(handler, options.maxConcurrency, options.maxQueue)
+		setBulkhead[TRequest](bulkhead)
+		handler = bulkhead
+	} else {
+		setBulkhead[TRequest](nil)
+	}
+	// Identical requests are deduplicated before they take a slot in the bulkhead
+	if options.singleflight {
+		handler = newSingleflightHandler(handler)
+	}
+	return handler
+}
+
+// SubscriberOption configures how a subscriber registered with RegisterSubscriber or
+// RegisterFallibleSubscriber is executed.
+type SubscriberOption func(*subscriberOptions)
+
+type subscriberOptions struct {
+	name     string
+	retry    RetryPolicy
+	sink     interfaces.DeadLetterSink
+	priority int
+	filter   any
+}
+
+func newSubscriberOptions(opts ...SubscriberOption) subscriberOptions {
+	var options subscriberOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// decorates reports whether the options require retrying or dead-lettering the subscriber.
+func (o subscriberOptions) decorates() bool {
+	return o.retry.MaxAttempts > 0 || o.retry.Backoff != nil || o.sink != nil
+}
+
+// WithSubscriberName names the subscriber in metrics, traces and dead letters. Defaults to the
+// type of the subscriber; set it to tell apart subscribers of the same type. Subscribers of an
+// event type sending dead letters to a sink must have distinct names, otherwise registration panics.
+func WithSubscriberName(name string) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.name = name
+	}
+}
+
+// WithRetry retries the subscriber when it returns an error or panics. The retries are not
+// cut short when the context passed to Publish is cancelled: the subscriber receives it
+// detached from cancellation.
+func WithRetry(policy RetryPolicy) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.retry = policy
+	}
+}
+
+// WithDeadLetterSink sends the events the subscriber still fails to handle after every retry
+// to the sink, from which they can be re-driven with Redrive. Registration panics if another
+// subscriber of the event type with the same name already has a sink.
+func WithDeadLetterSink(sink interfaces.DeadLetterSink) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.sink = sink
+	}
+}
+
+// WithPriority sets the priority of the subscriber. Subscribers of an event dispatched with
+// DispatchSequential run in descending order of priority, and in order of registration among
+// subscribers of the same priority. Defaults to 0.
+func WithPriority(priority int) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.priority = priority
+	}
+}
+
+// decorateSubscriber wraps the subscriber so that it is retried and dead-lettered according to
+// the registration options.
+func decorateSubscriber[TRequest any](handle func(TRequest, ...any) error, subscriber any, options subscriberOptions) interfaces.Subscriber[TRequest] {
+	if options.name == "" {
+		options.name = fmt.Sprintf("%T", subscriber)
+	}
+	resilient := &resilientSubscriber[TRequest]{handle: handle, options: options}
+	if options.sink != nil {
+		setRedriver[TRequest](options.name, resilient)
+	}
+	return resilient
+}
+
+// renamedSubscriber reports the name set with WithSubscriberName for a subscriber that is
+// neither retried nor dead-lettered, leaving its panics to the caller.
+type renamedSubscriber[TRequest any] struct {
+	subscriber interfaces.Subscriber[TRequest]
+	name       string
+}
+
+// Handle runs the wrapped subscriber.
+func (r *renamedSubscriber[TRequest]) Handle(request TRequest, params ...any) {
+	r.subscriber.Handle(request, params...)
+}
+
+// SubscriberName reports the name of the subscriber to the core registry.
+func (r *renamedSubscriber[TRequest]) SubscriberName() string {
+	return r.name
+}
@@ -0,0 +1,80 @@
+// Package otel adapts an OpenTelemetry tracer to the godiator Tracer interface.
+//
+// It is a separate module so that the godiator module does not depend on OpenTelemetry.
+package otel
+
+import (
+	"context"
+
+	"github.com/baranius/godiator/core/interfaces"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ interfaces.Tracer = (*Tracer)(nil)
+
+// Tracer starts the spans of the mediator with an OpenTelemetry tracer.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer creates a Tracer starting spans with the OpenTelemetry tracer.
+//
+// Parameters:
+//   - tracer: The OpenTelemetry tracer
+//
+// Returns:
+//   - *Tracer: The created tracer
+//
+// Example:
+//
+//	godiator.SetTracer(otel.NewTracer(otelapi.Tracer("godiator")))
+func NewTracer(tracer trace.Tracer) *Tracer {
+	return &Tracer{tracer: tracer}
+}
+
+// Start starts an OpenTelemetry span with the attributes and links of the config.
+//
+// Parameters:
+//   - ctx: The context of the parent span
+//   - name: The name of the span
+//   - config: The attributes and links of the span
+//
+// Returns:
+//   - context.Context: The context carrying the new span
+//   - interfaces.Span: The new span
+func (t *Tracer) Start(ctx context.Context, name string, config interfaces.SpanConfig) (context.Context, interfaces.Span) {
+	attributes := make([]attribute.KeyValue, 0, len(config.Attributes))
+	for key, value := range config.Attributes {
+		attributes = append(attributes, attribute.String(key, value))
+	}
+	opts := []trace.SpanStartOption{trace.WithAttributes(attributes...)}
+	for _, link := range config.Links {
+		if spanContext := trace.SpanContextFromContext(link); spanContext.IsValid() {
+			opts = append(opts, trace.WithLinks(trace.Link{SpanContext: spanContext}))
+		}
+	}
+	if config.Root {
+		opts = append(opts, trace.WithNewRoot())
+	}
+
+	ctx, span := t.tracer.Start(ctx, name, opts...)
+	return ctx, &otelSpan{span: span}
+}
+
+// otelSpan adapts an OpenTelemetry span to the godiator Span interface.
+type otelSpan struct {
+	span trace.Span
+}
+
+// SetError records the error on the span and sets its status to error.
+func (s *otelSpan) SetError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End completes the span.
+func (s *otelSpan) End() {
+	s.span.End()
+}
@@ -0,0 +1,72 @@
+package otel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/baranius/godiator/core/interfaces"
+	"github.com/baranius/godiator/otel"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type TracerTestSuite struct {
+	suite.Suite
+	recorder *tracetest.SpanRecorder
+	tracer   *otel.Tracer
+}
+
+// Run Tracer Test Suite
+func TestTracerTestSuite(t *testing.T) {
+	suite.Run(t, new(TracerTestSuite))
+}
+
+func (s *TracerTestSuite) SetupTest() {
+	s.recorder = tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(s.recorder))
+	s.tracer = otel.NewTracer(provider.Tracer("godiator"))
+}
+
+// Test spans are children of the span in the context with their attributes and error status
+func (s *TracerTestSuite) TestTracer_ChildSpan() {
+	// Given
+	ctx, parent := s.tracer.Start(context.Background(), "send", interfaces.SpanConfig{})
+
+	// When
+	_, child := s.tracer.Start(ctx, "handle", interfaces.SpanConfig{Attributes: map[string]string{"godiator.request_type": "Request"}})
+	child.SetError(errors.New("failed"))
+	child.End()
+	parent.End()
+
+	// Then
+	spans := s.recorder.Ended()
+	s.Len(spans, 2)
+	s.Equal("handle", spans[0].Name())
+	s.Equal(spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	s.Equal([]attribute.KeyValue{attribute.String("godiator.request_type", "Request")}, spans[0].Attributes())
+	s.Equal(codes.Error, spans[0].Status().Code)
+	s.Equal("failed", spans[0].Status().Description)
+}
+
+// Test root spans start a new trace linked to the given contexts
+func (s *TracerTestSuite) TestTracer_RootSpanWithLinks() {
+	// Given
+	publishCtx, publish := s.tracer.Start(context.Background(), "publish", interfaces.SpanConfig{})
+	publish.End()
+
+	// When
+	_, subscriber := s.tracer.Start(publishCtx, "subscriber", interfaces.SpanConfig{Links: []context.Context{publishCtx}, Root: true})
+	subscriber.End()
+
+	// Then
+	spans := s.recorder.Ended()
+	s.Len(spans, 2)
+	s.False(spans[1].Parent().IsValid())
+	s.NotEqual(spans[0].SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+	s.Len(spans[1].Links(), 1)
+	s.Equal(spans[0].SpanContext().SpanID(), spans[1].Links()[0].SpanContext.SpanID())
+}
@@ -0,0 +1,123 @@
+package godiator
+
+import (
+	"context"
+	"sync"
+
+	"github.com/baranius/godiator/core/contexts"
+)
+
+// Partitioned can be implemented by events to be delivered in order per key, such as an
+// aggregate ID. Each subscriber receives the events sharing a key one after the other, in
+// publish order, while other subscribers and other keys are delivered in parallel. Subscribers
+// sharing a name set WithSubscriberName share their order, so that a projection registered for
+// several event types receives them in order across types. The subscribers of events set to
+// DispatchSequential are ordered per event type and key as a whole.
+//
+// Without the event queue, a goroutine delivers the events of each subscriber and key in
+// flight, and up to 1024 of them wait their turn; Publish then waits for room until the context
+// found in params is done, unless it is called by a subscriber of a partitioned event with the
+// params it received, which could otherwise wait for itself. With the event queue, the events
+// of a subscriber and key are delivered by a single worker and wait in its own queue, holding
+// Capacity/Workers invocations under the overflow policy of the event queue. Enabling or
+// disabling the event queue does not order the events published afterwards behind those in
+// flight.
+//
+// Example:
+//
+//	func (e OrderShipped) PartitionKey() string {
+//	    return e.OrderID
+//	}
+type Partitioned interface {
+	PartitionKey() string
+}
+
+// laneCapacity is the number of invocations waiting on a lane without the event queue.
+const laneCapacity = 1024
+
+// partitionLane holds the invocations of a lane waiting for the previous ones to complete.
+type partitionLane struct {
+	pending []queuedInvocation
+	// room is closed once an invocation is taken off the full lane, to wake the publishers
+	room chan struct{}
+}
+
+var (
+	partitionsMu sync.Mutex
+	partitions   = make(map[string]*partitionLane)
+)
+
+// partitionedKey marks the context passed to the subscribers of partitioned events delivered
+// without the event queue.
+type partitionedKey struct{}
+
+// markPartitioned returns the params with a context marked as delivered on a lane, so that the
+// events the subscribers publish are not left waiting for their own lane.
+func markPartitioned(params []any) []any {
+	return contexts.With(context.WithValue(ContextFrom(params...), partitionedKey{}, true), params...)
+}
+
+// laneOf returns the lane of an invocation for the key of a partitioned event: the lane of its
+// subscriber, or of its event type for the sequences of events set to DispatchSequential.
+func laneOf(invocation queuedInvocation, key string) string {
+	if invocation.subscriber == "" {
+		return "\x00" + invocation.eventType + "\x00" + key
+	}
+	return invocation.subscriber + "\x00" + key
+}
+
+// deliverPartitioned queues the invocation behind the invocations published earlier on its lane,
+// starting a goroutine for the lane if none is running, and waits for room while the lane is
+// full. The event queue delivers lanes itself.
+func deliverPartitioned(invocation queuedInvocation, params []any) error {
+	ctx := ContextFrom(params...)
+	nested := ctx.Value(partitionedKey{}) != nil
+	for {
+		partitionsMu.Lock()
+		lane, ok := partitions[invocation.lane]
+		if !ok {
+			lane = &partitionLane{pending: []queuedInvocation{invocation}}
+			partitions[invocation.lane] = lane
+			partitionsMu.Unlock()
+			go lane.run(invocation.lane)
+			return nil
+		}
+		if nested || len(lane.pending) < laneCapacity {
+			lane.pending = append(lane.pending, invocation)
+			partitionsMu.Unlock()
+			return nil
+		}
+		if lane.room == nil {
+			lane.room = make(chan struct{})
+		}
+		room := lane.room
+		partitionsMu.Unlock()
+
+		select {
+		case <-room:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// run executes the invocations of the lane in order until none is left.
+func (l *partitionLane) run(key string) {
+	for {
+		partitionsMu.Lock()
+		if len(l.pending) == 0 {
+			delete(partitions, key)
+			partitionsMu.Unlock()
+			return
+		}
+		invocation := l.pending[0]
+		l.pending = l.pending[1:]
+		if l.room != nil {
+			close(l.room)
+			l.room = nil
+		}
+		partitionsMu.Unlock()
+
+		invocation.execute()
+	}
+}
@@ -0,0 +1,138 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/core/contexts"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var _ interfaces.Pipeline = (*Authorization)(nil)
+
+// ErrForbidden is matched by every *ForbiddenError with errors.Is.
+var ErrForbidden = errors.New("forbidden")
+
+// ForbiddenError is returned when the principal of a request is not allowed to send it.
+// When a policy forbids the request, Err holds the error it returned.
+type ForbiddenError struct {
+	RequestType string
+	Reason      string
+	Err         error
+}
+
+// Error implements the error interface.
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf(`request "%s" is forbidden: %s`, e.RequestType, e.Reason)
+}
+
+// Is reports whether the target is ErrForbidden.
+func (e *ForbiddenError) Is(target error) bool {
+	return target == ErrForbidden
+}
+
+// Unwrap returns the error of the policy that forbade the request, if any.
+func (e *ForbiddenError) Unwrap() error {
+	return e.Err
+}
+
+// PermissionRequirer can be implemented by requests to declare the permissions their
+// principal must have. Declaring no permission is the same as not implementing it.
+//
+// Example:
+//
+//	func (c DeleteOrderCommand) RequiresPermission() []string {
+//	    return []string{"orders:delete"}
+//	}
+type PermissionRequirer interface {
+	RequiresPermission() []string
+}
+
+// AuthorizationOption configures an Authorization pipeline.
+type AuthorizationOption func(*Authorization)
+
+// WithDefaultDeny forbids requests whose type has no registered policy and that declare no
+// permission with PermissionRequirer. By default such requests are allowed.
+func WithDefaultDeny() AuthorizationOption {
+	return func(p *Authorization) {
+		p.defaultDeny = true
+	}
+}
+
+// Authorization is a pipeline that authorizes requests before they reach the handler.
+// The principal is taken from the context in params, see godiator.ContextWithPrincipal.
+// Requests implementing PermissionRequirer need a principal holding every permission they
+// declare, and every policy registered with godiator.RegisterPolicy for the request type
+// must pass.
+type Authorization struct {
+	BasePipeline
+	defaultDeny bool
+}
+
+// NewAuthorization creates an Authorization pipeline.
+//
+// Parameters:
+//   - opts: Options changing how unregistered request types are handled
+//
+// Returns:
+//   - *Authorization: The created pipeline
+func NewAuthorization(opts ...AuthorizationOption) *Authorization {
+	p := &Authorization{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Handle authorizes the request and calls the next pipeline only if it is allowed.
+//
+// Parameters:
+//   - request: The request object to process
+//   - params: Optional additional parameters passed to the pipeline
+//
+// Returns:
+//   - any: The response from the next pipeline, or nil if the request is forbidden
+//   - error: A *ForbiddenError if the request is forbidden, or an error from the next pipeline
+func (p *Authorization) Handle(request any, params ...any) (any, error) {
+	if reason, err := p.authorize(request, params...); reason != "" || err != nil {
+		return nil, &ForbiddenError{RequestType: reflect.TypeOf(request).String(), Reason: reason, Err: err}
+	}
+	return p.Next().Handle(request, params...)
+}
+
+// authorize returns why the request is forbidden, with the error of the policy that forbade it,
+// or an empty string if it is allowed.
+func (p *Authorization) authorize(request any, params ...any) (string, error) {
+	principal, authenticated := contexts.PrincipalFrom(params...)
+	policies := core.GetPolicies(request)
+	var permissions []string
+	if requirer, ok := request.(PermissionRequirer); ok {
+		permissions = requirer.RequiresPermission()
+	}
+
+	if len(permissions) == 0 && len(policies) == 0 {
+		if p.defaultDeny {
+			return "no authorization policy", nil
+		}
+		return "", nil
+	}
+
+	for _, permission := range permissions {
+		if !authenticated {
+			return "no principal", nil
+		}
+		if !principal.HasPermission(permission) {
+			return fmt.Sprintf(`missing permission "%s"`, permission), nil
+		}
+	}
+
+	ctx := contexts.From(params...)
+	for _, policy := range policies {
+		if err := policy(ctx, principal, request); err != nil {
+			return err.Error(), err
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,104 @@
+package pipeline
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var _ interfaces.Pipeline = (*Caching)(nil)
+
+// Cacheable can be implemented by requests whose responses may be served from a cache.
+//
+// Example:
+//
+//	func (r GetProductRequest) CacheKey() string      { return "product:" + r.SKU }
+//	func (r GetProductRequest) TTL() time.Duration    { return time.Minute }
+type Cacheable interface {
+	CacheKey() string
+	TTL() time.Duration
+}
+
+// CacheTagger can be implemented by Cacheable requests to associate their cached
+// responses with tags, so that related entries can be invalidated together.
+type CacheTagger interface {
+	CacheTags() []string
+}
+
+// CacheEntryKey returns the key under which the Caching pipeline stores the response of the
+// request: its cache key prefixed with its type, so that request types sharing a cache key do
+// not share entries. Use it to invalidate the entry of a request by key.
+//
+// Parameters:
+//   - request: The request whose entry key is returned
+//
+// Returns:
+//   - string: The key of the entry in the cache
+//
+// Example:
+//
+//	godiator.InvalidateCacheOn(cache, func(e ProductUpdatedEvent) godiator.CacheInvalidation {
+//	    return godiator.CacheInvalidation{Keys: []string{pipeline.CacheEntryKey(GetProductRequest{SKU: e.SKU})}}
+//	})
+func CacheEntryKey(request Cacheable) string {
+	return reflect.TypeOf(request).String() + ":" + request.CacheKey()
+}
+
+// Caching is a pipeline that serves responses of Cacheable requests from a cache before
+// they reach the handler. Successful responses are stored under the CacheEntryKey of the
+// request for its TTL; errors are never cached. Other requests are passed through unchanged.
+//
+// Use godiator.InvalidateCacheOn to clear stale entries when an event is published. A request
+// that reached the handler before the invalidation ran still stores its response afterwards,
+// so the entry may hold a result read before the change until its TTL expires; keep TTLs short
+// for data that changes while it is being read.
+type Caching struct {
+	BasePipeline
+	cache interfaces.Cache
+}
+
+// NewCaching creates a Caching pipeline backed by the cache.
+//
+// Parameters:
+//   - cache: The cache used to store responses
+//
+// Returns:
+//   - *Caching: The created pipeline
+func NewCaching(cache interfaces.Cache) *Caching {
+	return &Caching{cache: cache}
+}
+
+// Handle returns the cached response for Cacheable requests or calls the next pipeline
+// and caches its response.
+//
+// Parameters:
+//   - request: The request object to process
+//   - params: Optional additional parameters passed to the pipeline
+//
+// Returns:
+//   - any: The cached response or the response from the next pipeline
+//   - error: An error if processing fails
+func (p *Caching) Handle(request any, params ...any) (any, error) {
+	cacheable, ok := request.(Cacheable)
+	if !ok || cacheable.TTL() <= 0 {
+		return p.Next().Handle(request, params...)
+	}
+
+	key := CacheEntryKey(cacheable)
+	if response, found := p.cache.Get(key); found {
+		return response, nil
+	}
+
+	response, err := p.Next().Handle(request, params...)
+	if err != nil {
+		return response, err
+	}
+
+	var tags []string
+	if tagger, ok := request.(CacheTagger); ok {
+		tags = tagger.CacheTags()
+	}
+	p.cache.Set(key, response, cacheable.TTL(), tags...)
+	return response, nil
+}
@@ -0,0 +1,311 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var _ interfaces.Pipeline = (*CircuitBreaker)(nil)
+
+// ErrCircuitOpen is returned, wrapped with the request type, when a circuit is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of the circuit of a request type.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every request until the cool-down elapses.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of trial requests through.
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitStateChanged is published with godiator.Publish whenever the circuit of a request
+// type changes state. Register a subscriber for it with godiator.RegisterSubscriber to be
+// notified; it is not published while no subscriber is registered. State changes are published
+// in order from a background goroutine, so that a full event queue never stalls requests.
+type CircuitStateChanged struct {
+	RequestType string
+	From        CircuitState
+	To          CircuitState
+	ChangedAt   time.Time
+}
+
+// CircuitBreakerOption configures a CircuitBreaker pipeline.
+type CircuitBreakerOption func(*CircuitBreaker)
+
+// WithFailureThreshold sets the number of consecutive failures that opens the circuit. Defaults to 5.
+func WithFailureThreshold(threshold int) CircuitBreakerOption {
+	return func(p *CircuitBreaker) {
+		if threshold > 0 {
+			p.failureThreshold = threshold
+		}
+	}
+}
+
+// WithCoolDown sets how long the circuit stays open before trial requests are let through. Defaults to 30s.
+func WithCoolDown(coolDown time.Duration) CircuitBreakerOption {
+	return func(p *CircuitBreaker) {
+		if coolDown > 0 {
+			p.coolDown = coolDown
+		}
+	}
+}
+
+// WithHalfOpenRequests sets the number of trial requests let through while half-open.
+// The circuit closes once all of them succeed. Defaults to 1.
+func WithHalfOpenRequests(requests int) CircuitBreakerOption {
+	return func(p *CircuitBreaker) {
+		if requests > 0 {
+			p.halfOpenRequests = requests
+		}
+	}
+}
+
+// WithFailureFilter sets which errors count as failures. By default every error does except
+// context.Canceled, returned when the caller gave up; use it to ignore errors such as
+// validation failures that say nothing about the downstream.
+func WithFailureFilter(isFailure func(err error) bool) CircuitBreakerOption {
+	return func(p *CircuitBreaker) {
+		if isFailure != nil {
+			p.isFailure = isFailure
+		}
+	}
+}
+
+// WithCircuitClock replaces the clock used to time the cool-down.
+func WithCircuitClock(clock Clock) CircuitBreakerOption {
+	return func(p *CircuitBreaker) {
+		if clock != nil {
+			p.clock = clock
+		}
+	}
+}
+
+// circuit tracks the state of a single request type.
+type circuit struct {
+	state     CircuitState
+	failures  int
+	trials    int
+	successes int
+	openedAt  time.Time
+}
+
+// CircuitBreaker is a pipeline that stops calling the chain below it for a request type once
+// it keeps failing. After a number of consecutive failures the circuit opens and requests
+// fail fast with ErrCircuitOpen. Once the cool-down elapses the circuit becomes half-open and
+// lets trial requests through: it closes if they succeed and opens again if any fails.
+type CircuitBreaker struct {
+	BasePipeline
+	failureThreshold int
+	coolDown         time.Duration
+	halfOpenRequests int
+	isFailure        func(err error) bool
+	clock            Clock
+
+	mu       sync.Mutex
+	circuits map[reflect.Type]*circuit
+
+	// changesMu guards the state changes waiting to be published
+	changesMu  sync.Mutex
+	changes    []CircuitStateChanged
+	publishing bool
+}
+
+// NewCircuitBreaker creates a CircuitBreaker pipeline.
+//
+// Parameters:
+//   - opts: Options overriding the default thresholds
+//
+// Returns:
+//   - *CircuitBreaker: The created pipeline
+func NewCircuitBreaker(opts ...CircuitBreakerOption) *CircuitBreaker {
+	p := &CircuitBreaker{
+		failureThreshold: 5,
+		coolDown:         30 * time.Second,
+		halfOpenRequests: 1,
+		isFailure:        func(err error) bool { return err != nil && !errors.Is(err, context.Canceled) },
+		clock:            realClock{},
+		circuits:         make(map[reflect.Type]*circuit),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// State returns the current state of the circuit of the request type.
+//
+// Parameters:
+//   - request: A request of the type to inspect
+//
+// Returns:
+//   - CircuitState: The state of the circuit
+func (p *CircuitBreaker) State(request any) CircuitState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.circuits[reflect.TypeOf(request)]; ok {
+		return c.state
+	}
+	return CircuitClosed
+}
+
+// Handle calls the next pipeline unless the circuit of the request type is open.
+//
+// Parameters:
+//   - request: The request object to process
+//   - params: Optional additional parameters passed to the pipeline
+//
+// Returns:
+//   - any: The response from the next pipeline, or nil if the circuit is open
+//   - error: An error wrapping ErrCircuitOpen, or an error from the next pipeline
+func (p *CircuitBreaker) Handle(request any, params ...any) (any, error) {
+	requestType := reflect.TypeOf(request)
+	if !p.acquire(requestType) {
+		return nil, fmt.Errorf(`%w for "%s"`, ErrCircuitOpen, requestType.String())
+	}
+
+	completed := false
+	var err error
+	defer func() {
+		// A panic counts as a failure
+		p.release(requestType, !completed || p.isFailure(err))
+	}()
+
+	response, err := p.Next().Handle(request, params...)
+	completed = true
+	return response, err
+}
+
+// acquire reports whether a request may go through, moving an open circuit to half-open
+// once its cool-down has elapsed.
+func (p *CircuitBreaker) acquire(requestType reflect.Type) bool {
+	p.mu.Lock()
+	c := p.circuits[requestType]
+	if c == nil {
+		c = &circuit{}
+		p.circuits[requestType] = c
+	}
+
+	var change *CircuitStateChanged
+	if c.state == CircuitOpen && p.clock.Now().Sub(c.openedAt) >= p.coolDown {
+		change = p.transition(requestType, c, CircuitHalfOpen)
+	}
+
+	allowed := true
+	switch c.state {
+	case CircuitOpen:
+		allowed = false
+	case CircuitHalfOpen:
+		if c.trials >= p.halfOpenRequests {
+			allowed = false
+		} else {
+			c.trials++
+		}
+	}
+	p.mu.Unlock()
+
+	p.publish(change)
+	return allowed
+}
+
+// release records the outcome of a request that went through.
+func (p *CircuitBreaker) release(requestType reflect.Type, failed bool) {
+	p.mu.Lock()
+	c := p.circuits[requestType]
+
+	var change *CircuitStateChanged
+	switch c.state {
+	case CircuitClosed:
+		if !failed {
+			c.failures = 0
+		} else if c.failures++; c.failures >= p.failureThreshold {
+			change = p.transition(requestType, c, CircuitOpen)
+		}
+	case CircuitHalfOpen:
+		if failed {
+			change = p.transition(requestType, c, CircuitOpen)
+		} else if c.successes++; c.successes >= p.halfOpenRequests {
+			change = p.transition(requestType, c, CircuitClosed)
+		}
+	}
+	p.mu.Unlock()
+
+	p.publish(change)
+}
+
+// transition moves the circuit to the state and resets its counters. The caller must hold the lock.
+func (p *CircuitBreaker) transition(requestType reflect.Type, c *circuit, to CircuitState) *CircuitStateChanged {
+	change := &CircuitStateChanged{RequestType: requestType.String(), From: c.state, To: to, ChangedAt: p.clock.Now()}
+	c.state = to
+	c.failures, c.trials, c.successes = 0, 0, 0
+	if to == CircuitOpen {
+		c.openedAt = change.ChangedAt
+	}
+	return change
+}
+
+// publish queues the state change to be published by a background goroutine, starting one if
+// none is running, so that the changes are published in order without blocking the request.
+func (p *CircuitBreaker) publish(change *CircuitStateChanged) {
+	if change == nil || len(core.GetSubscribers[CircuitStateChanged]()) == 0 {
+		return
+	}
+	publish, ok := core.GetPublisher[CircuitStateChanged]()
+	if !ok {
+		return
+	}
+
+	p.changesMu.Lock()
+	defer p.changesMu.Unlock()
+
+	p.changes = append(p.changes, *change)
+	if !p.publishing {
+		p.publishing = true
+		go p.publishChanges(publish)
+	}
+}
+
+// publishChanges publishes the queued state changes until none is left.
+func (p *CircuitBreaker) publishChanges(publish func(change CircuitStateChanged, params ...any) error) {
+	for {
+		p.changesMu.Lock()
+		if len(p.changes) == 0 {
+			p.publishing = false
+			p.changesMu.Unlock()
+			return
+		}
+		change := p.changes[0]
+		p.changes = p.changes[1:]
+		p.changesMu.Unlock()
+
+		if err := publish(change); err != nil {
+			slog.Warn("publishing circuit state change", "request_type", change.RequestType, "to", change.To.String(), "error", err)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/baranius/godiator/core/contexts"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var _ interfaces.Pipeline = (*Idempotency)(nil)
+
+// Idempotent can be implemented by commands that must be executed at most once per key.
+//
+// Example:
+//
+//	func (c ChargePaymentCommand) IdempotencyKey() string {
+//	    return c.PaymentID
+//	}
+type Idempotent interface {
+	IdempotencyKey() string
+}
+
+// idempotencyCall is an in-progress execution that concurrent duplicates wait for.
+type idempotencyCall struct {
+	done     chan struct{}
+	response any
+	err      error
+}
+
+// Idempotency is a pipeline that executes Idempotent requests at most once. The response of
+// the first successful execution is saved in the store under the request type and key, and
+// replayed to every later request with the same key. Failed executions are not saved, so the
+// request can be retried with the same key, unless WithErrorReplay opts in to replaying them.
+// Concurrent duplicates wait for the in-progress execution instead of running the handler again,
+// or until the context found in their params is done.
+// Other requests are passed through unchanged.
+type Idempotency struct {
+	BasePipeline
+	store       IdempotencyStore
+	replayError func(err error) bool
+
+	mu       sync.Mutex
+	inFlight map[string]*idempotencyCall
+}
+
+// IdempotencyOption configures an Idempotency pipeline.
+type IdempotencyOption func(*Idempotency)
+
+// WithErrorReplay saves the failed executions whose error is accepted by the filter, such as
+// a declined card, and replays them to later requests with the same key. A nil filter accepts
+// every error. Replayed errors only keep the message of the original error. By default failed
+// executions are not saved, so that transient failures can be retried.
+func WithErrorReplay(replay func(err error) bool) IdempotencyOption {
+	return func(p *Idempotency) {
+		if replay == nil {
+			replay = func(error) bool { return true }
+		}
+		p.replayError = replay
+	}
+}
+
+// NewIdempotency creates an Idempotency pipeline backed by the store.
+//
+// Parameters:
+//   - store: The store holding the outcome of executed requests
+//   - opts: Options controlling which outcomes are saved
+//
+// Returns:
+//   - *Idempotency: The created pipeline
+func NewIdempotency(store IdempotencyStore, opts ...IdempotencyOption) *Idempotency {
+	p := &Idempotency{store: store, inFlight: make(map[string]*idempotencyCall)}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Handle replays the stored outcome of Idempotent requests or calls the next pipeline and
+// stores its outcome.
+//
+// Parameters:
+//   - request: The request object to process
+//   - params: Optional additional parameters passed to the pipeline
+//
+// Returns:
+//   - any: The stored response or the response from the next pipeline
+//   - error: The stored error, an error from the next pipeline, or an error reading the store.
+//     Failing to save the outcome is logged rather than returned, as the request has already been executed
+func (p *Idempotency) Handle(request any, params ...any) (any, error) {
+	idempotent, ok := request.(Idempotent)
+	if !ok {
+		return p.Next().Handle(request, params...)
+	}
+	key := reflect.TypeOf(request).String() + ":" + idempotent.IdempotencyKey()
+
+	p.mu.Lock()
+	if call, found := p.inFlight[key]; found {
+		p.mu.Unlock()
+		ctx := contexts.From(params...)
+		select {
+		case <-call.done:
+			return call.response, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	call := &idempotencyCall{done: make(chan struct{})}
+	p.inFlight[key] = call
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		delete(p.inFlight, key)
+		p.mu.Unlock()
+		close(call.done)
+	}()
+
+	record, found, err := p.store.Get(key)
+	if err != nil {
+		call.err = fmt.Errorf("reading idempotency record: %w", err)
+		return nil, call.err
+	}
+	if found {
+		call.response, call.err = replay(record)
+		return call.response, call.err
+	}
+
+	// Kept for waiting duplicates if the chain panics
+	call.err = errors.New("idempotent request panicked")
+	call.response, call.err = p.Next().Handle(request, params...)
+
+	record = IdempotencyRecord{Response: call.response, CompletedAt: time.Now()}
+	if call.err != nil {
+		if p.replayError == nil || !p.replayError(call.err) {
+			return call.response, call.err
+		}
+		record.Err = call.err.Error()
+	}
+	// Reporting the failure to the caller would invite a retry executing the request again
+	if err := p.store.Put(key, record); err != nil {
+		slog.Error("saving idempotency record", "key", key, "error", err)
+	}
+	return call.response, call.err
+}
+
+func replay(record IdempotencyRecord) (any, error) {
+	if record.Err != "" {
+		return record.Response, errors.New(record.Err)
+	}
+	return record.Response, nil
+}
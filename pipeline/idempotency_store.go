@@ -0,0 +1,164 @@
+package pipeline
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// IdempotencyRecord is the stored outcome of an idempotent request.
+type IdempotencyRecord struct {
+	// Response is the response returned by the handler.
+	Response any
+	// Err is the message of the error returned by the handler, empty on success.
+	Err string
+	// CompletedAt is when the handler completed.
+	CompletedAt time.Time
+}
+
+// IdempotencyStore persists the outcome of idempotent requests. Implementations must be
+// safe for concurrent use.
+type IdempotencyStore interface {
+	Get(key string) (IdempotencyRecord, bool, error)
+	Put(key string, record IdempotencyRecord) error
+}
+
+var (
+	_ IdempotencyStore = (*MemoryIdempotencyStore)(nil)
+	_ IdempotencyStore = (*FileIdempotencyStore)(nil)
+)
+
+// MemoryIdempotencyStore is an IdempotencyStore keeping records in memory.
+// Records are lost when the process exits.
+type MemoryIdempotencyStore struct {
+	mu      sync.RWMutex
+	records map[string]IdempotencyRecord
+}
+
+// NewMemoryIdempotencyStore creates an empty MemoryIdempotencyStore.
+//
+// Returns:
+//   - *MemoryIdempotencyStore: The created store
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: make(map[string]IdempotencyRecord)}
+}
+
+// Get returns the record stored under the key.
+//
+// Parameters:
+//   - key: The idempotency key
+//
+// Returns:
+//   - IdempotencyRecord: The stored record
+//   - bool: Indicates whether a record was found
+//   - error: Always nil
+func (s *MemoryIdempotencyStore) Get(key string) (IdempotencyRecord, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, ok := s.records[key]
+	return record, ok, nil
+}
+
+// Put stores the record under the key, replacing any existing record.
+//
+// Parameters:
+//   - key: The idempotency key
+//   - record: The record to store
+//
+// Returns:
+//   - error: Always nil
+func (s *MemoryIdempotencyStore) Put(key string, record IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[key] = record
+	return nil
+}
+
+// FileIdempotencyStore is an IdempotencyStore keeping one gob encoded file per record in a
+// directory, so that records survive restarts. Concrete response types must be registered
+// with gob.Register before they are stored.
+type FileIdempotencyStore struct {
+	dir string
+}
+
+// NewFileIdempotencyStore creates a FileIdempotencyStore in the directory, creating it if needed.
+//
+// Parameters:
+//   - dir: The directory holding the records
+//
+// Returns:
+//   - *FileIdempotencyStore: The created store
+//   - error: An error if the directory cannot be created
+func NewFileIdempotencyStore(dir string) (*FileIdempotencyStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileIdempotencyStore{dir: dir}, nil
+}
+
+// Get reads the record stored under the key.
+//
+// Parameters:
+//   - key: The idempotency key
+//
+// Returns:
+//   - IdempotencyRecord: The stored record
+//   - bool: Indicates whether a record was found
+//   - error: An error if the record cannot be read
+func (s *FileIdempotencyStore) Get(key string) (IdempotencyRecord, bool, error) {
+	var record IdempotencyRecord
+
+	file, err := os.Open(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return record, false, nil
+	}
+	if err != nil {
+		return record, false, err
+	}
+	defer file.Close()
+
+	if err := gob.NewDecoder(file).Decode(&record); err != nil {
+		return record, false, err
+	}
+	return record, true, nil
+}
+
+// Put writes the record under the key, replacing any existing record. The file is written
+// to a temporary location first so that readers never observe a partial record.
+//
+// Parameters:
+//   - key: The idempotency key
+//   - record: The record to store
+//
+// Returns:
+//   - error: An error if the record cannot be written
+func (s *FileIdempotencyStore) Put(key string, record IdempotencyRecord) error {
+	file, err := os.CreateTemp(s.dir, ".record-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if err := gob.NewEncoder(file).Encode(&record); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), s.path(key))
+}
+
+// path returns the file of the key. Keys are hashed as they may contain any character.
+func (s *FileIdempotencyStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".gob")
+}
@@ -0,0 +1,245 @@
+package pipeline
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"reflect"
+	"time"
+
+	"github.com/baranius/godiator/core/contexts"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var _ interfaces.Pipeline = (*Logging)(nil)
+
+// redactedValue replaces the value of redacted fields in logged payloads.
+const redactedValue = "[REDACTED]"
+
+// maxRedactDepth bounds how deep nested payloads are walked, guarding against cycles.
+const maxRedactDepth = 8
+
+// Redactor can be implemented by requests and responses to choose what is logged in place
+// of themselves, such as a copy with sensitive fields masked. It takes precedence over
+// `godiator:"redact"` struct tags.
+//
+// Example:
+//
+//	func (r LoginRequest) Redact() any {
+//	    return LoginRequest{Username: r.Username}
+//	}
+type Redactor interface {
+	Redact() any
+}
+
+// LoggingOption configures a Logging pipeline.
+type LoggingOption func(*Logging)
+
+// WithPayloads logs the request and the response of every request. Fields tagged with
+// `godiator:"redact"` are masked, and values implementing Redactor are replaced by their
+// redacted form. Payloads are not logged by default.
+func WithPayloads() LoggingOption {
+	return func(p *Logging) {
+		p.payloads = true
+	}
+}
+
+// WithLogLevel sets the level of successful requests without a specific one. Defaults to slog.LevelInfo.
+// Failed requests are always logged at slog.LevelError.
+func WithLogLevel(level slog.Level) LoggingOption {
+	return func(p *Logging) {
+		p.defaultLevel = level
+	}
+}
+
+// LogLevelFor sets the level of successful requests of a specific request type, overriding the default.
+//
+// Type parameters:
+//   - TRequest: The request type the level applies to
+//
+// Parameters:
+//   - level: The level of successful requests
+func LogLevelFor[TRequest any](level slog.Level) LoggingOption {
+	return func(p *Logging) {
+		var request TRequest
+		p.levels[reflect.TypeOf(request)] = level
+	}
+}
+
+// LogSampleRateFor sets the fraction of successful requests of a specific request type that
+// are logged, such as 0.01 for one in a hundred. Failed requests are always logged.
+//
+// Type parameters:
+//   - TRequest: The request type the sample rate applies to
+//
+// Parameters:
+//   - rate: The fraction of requests logged, from 0 to 1
+func LogSampleRateFor[TRequest any](rate float64) LoggingOption {
+	return func(p *Logging) {
+		var request TRequest
+		p.sampleRates[reflect.TypeOf(request)] = rate
+	}
+}
+
+// Logging is a pipeline that logs every request with log/slog once the chain below it
+// completes. Records carry the request type, the duration, the outcome and, when it fails,
+// the error, and are written with the context found in params (see godiator.ContextFrom).
+type Logging struct {
+	BasePipeline
+	logger       *slog.Logger
+	payloads     bool
+	defaultLevel slog.Level
+	levels       map[reflect.Type]slog.Level
+	sampleRates  map[reflect.Type]float64
+}
+
+// NewLogging creates a Logging pipeline.
+//
+// Parameters:
+//   - logger: The logger records are written to, nil uses slog.Default()
+//   - opts: Payload, level and sampling options
+//
+// Returns:
+//   - *Logging: The created pipeline
+func NewLogging(logger *slog.Logger, opts ...LoggingOption) *Logging {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	p := &Logging{
+		logger:       logger,
+		defaultLevel: slog.LevelInfo,
+		levels:       make(map[reflect.Type]slog.Level),
+		sampleRates:  make(map[reflect.Type]float64),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Handle calls the next pipeline and logs the outcome.
+//
+// Parameters:
+//   - request: The request object to process
+//   - params: Optional additional parameters passed to the pipeline
+//
+// Returns:
+//   - any: The response from the next pipeline
+//   - error: An error from the next pipeline
+func (p *Logging) Handle(request any, params ...any) (any, error) {
+	start := time.Now()
+	response, err := p.Next().Handle(request, params...)
+	duration := time.Since(start)
+
+	requestType := reflect.TypeOf(request)
+	level, ok := p.levels[requestType]
+	if !ok {
+		level = p.defaultLevel
+	}
+	message, outcome := "request handled", "success"
+	if err != nil {
+		level, message, outcome = slog.LevelError, "request failed", "error"
+	} else if rate, ok := p.sampleRates[requestType]; ok && rand.Float64() >= rate {
+		return response, err
+	}
+
+	ctx := contexts.From(params...)
+	if !p.logger.Enabled(ctx, level) {
+		return response, err
+	}
+
+	attrs := []slog.Attr{
+		slog.String("request_type", fmt.Sprint(requestType)),
+		slog.Duration("duration", duration),
+		slog.String("outcome", outcome),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	if p.payloads {
+		attrs = append(attrs, slog.Any("request", redact(reflect.ValueOf(request), 0)))
+		if err == nil {
+			attrs = append(attrs, slog.Any("response", redact(reflect.ValueOf(response), 0)))
+		}
+	}
+	p.logger.LogAttrs(ctx, level, message, attrs...)
+	return response, err
+}
+
+// redact converts the value into maps and slices of plain values, masking fields tagged
+// with `godiator:"redact"` and replacing Redactor values with their redacted form.
+func redact(v reflect.Value, depth int) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if depth > maxRedactDepth {
+		return fmt.Sprintf("%T", v.Interface())
+	}
+	if v.CanInterface() {
+		if redactor, ok := v.Interface().(Redactor); ok {
+			if v.Kind() != reflect.Pointer || !v.IsNil() {
+				return redactFields(reflect.ValueOf(redactor.Redact()), depth+1)
+			}
+		}
+	}
+	return redactFields(v, depth)
+}
+
+// redactFields is redact without the Redactor check, so that redacted forms of the same
+// type are not redacted again.
+func redactFields(v reflect.Value, depth int) any {
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactFields(v.Elem(), depth+1)
+	case reflect.Struct:
+		if _, ok := v.Interface().(time.Time); ok {
+			return v.Interface()
+		}
+		fields := make(map[string]any, v.NumField())
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Tag.Get("godiator") == "redact" {
+				fields[field.Name] = redactedValue
+				continue
+			}
+			fields[field.Name] = redact(v.Field(i), depth+1)
+		}
+		return fields
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return fmt.Sprintf("[%d bytes]", v.Len())
+		}
+		items := make([]any, v.Len())
+		for i := range v.Len() {
+			items[i] = redact(v.Index(i), depth+1)
+		}
+		return items
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		entries := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entries[fmt.Sprint(iter.Key().Interface())] = redact(iter.Value(), depth+1)
+		}
+		return entries
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return v.Type().String()
+	default:
+		return v.Interface()
+	}
+}
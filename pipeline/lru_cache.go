@@ -0,0 +1,154 @@
+package pipeline
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var _ interfaces.Cache = (*LRUCache)(nil)
+
+// lruEntry is a single cached value along with its expiry and tags.
+type lruEntry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+	tags      []string
+}
+
+// LRUCache is an in-memory Cache that evicts the least recently used entry once
+// its capacity is reached. Expired entries are removed when they are read.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	tags     map[string]map[string]struct{}
+}
+
+// NewLRUCache creates an LRUCache holding at most capacity entries.
+//
+// Parameters:
+//   - capacity: The maximum number of entries, values below 1 are treated as 1
+//
+// Returns:
+//   - *LRUCache: The created cache
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		tags:     make(map[string]map[string]struct{}),
+	}
+}
+
+// Get returns the value cached under the key if it exists and has not expired.
+//
+// Parameters:
+//   - key: The cache key
+//
+// Returns:
+//   - any: The cached value
+//   - bool: Indicates whether the value was found
+func (c *LRUCache) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*lruEntry)
+	if !time.Now().Before(entry.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// Set caches the value under the key for the given duration, replacing any existing entry.
+//
+// Parameters:
+//   - key: The cache key
+//   - value: The value to cache
+//   - ttl: How long the value stays valid
+//   - tags: Optional tags used to invalidate the entry with DeleteTags
+func (c *LRUCache) Set(key string, value any, ttl time.Duration, tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	entry := &lruEntry{key: key, value: value, expiresAt: time.Now().Add(ttl), tags: tags}
+	c.entries[key] = c.order.PushFront(entry)
+	for _, tag := range tags {
+		if c.tags[tag] == nil {
+			c.tags[tag] = make(map[string]struct{})
+		}
+		c.tags[tag][key] = struct{}{}
+	}
+
+	if c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// Delete removes the entries cached under the keys.
+//
+// Parameters:
+//   - keys: The cache keys to remove
+func (c *LRUCache) Delete(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+		}
+	}
+}
+
+// DeleteTags removes every entry associated with any of the tags.
+//
+// Parameters:
+//   - tags: The tags whose entries should be removed
+func (c *LRUCache) DeleteTags(tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			if element, ok := c.entries[key]; ok {
+				c.remove(element)
+			}
+		}
+	}
+}
+
+// Len returns the number of entries currently held, including expired entries not yet removed.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// remove deletes the element from every index. The caller must hold the lock.
+func (c *LRUCache) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*lruEntry)
+	delete(c.entries, entry.key)
+	for _, tag := range entry.tags {
+		delete(c.tags[tag], entry.key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
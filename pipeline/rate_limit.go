@@ -0,0 +1,265 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/baranius/godiator/core/contexts"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var _ interfaces.Pipeline = (*RateLimit)(nil)
+
+// ErrRateLimited is matched by every *RateLimitedError with errors.Is.
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimitedError is returned when a request exceeds its rate limit.
+type RateLimitedError struct {
+	RequestType string
+	Key         string
+	RetryAfter  time.Duration
+}
+
+// Error implements the error interface.
+func (e *RateLimitedError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf(`request "%s" is rate limited, retry after %s`, e.RequestType, e.RetryAfter)
+	}
+	return fmt.Sprintf(`request "%s" is rate limited for key "%s", retry after %s`, e.RequestType, e.Key, e.RetryAfter)
+}
+
+// Is reports whether the target is ErrRateLimited.
+func (e *RateLimitedError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// RateLimitKeyer can be implemented by requests to be rate limited per key, such as a
+// tenant or user ID, instead of per request type.
+type RateLimitKeyer interface {
+	RateLimitKey() string
+}
+
+// Limit is the configuration of a token bucket.
+type Limit struct {
+	// Rate is the number of tokens added per second. Zero or less means unlimited.
+	Rate float64
+	// Burst is the capacity of the bucket. Values below 1 are treated as 1.
+	Burst int
+}
+
+// RateLimitMode decides what happens to requests exceeding their limit.
+type RateLimitMode int
+
+const (
+	// RateLimitReject returns a *RateLimitedError immediately.
+	RateLimitReject RateLimitMode = iota
+	// RateLimitWait waits until a token is available, or the request context is done.
+	RateLimitWait
+)
+
+// Clock provides the current time and timers to the RateLimit and CircuitBreaker pipelines.
+// Tests can inject a fake implementation with WithClock or WithCircuitClock.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// RateLimitOption configures a RateLimit pipeline.
+type RateLimitOption func(*RateLimit)
+
+// RateLimitFor sets the limit of a specific request type, overriding the default.
+//
+// Type parameters:
+//   - TRequest: The request type the limit applies to
+//
+// Parameters:
+//   - limit: The limit of the request type
+func RateLimitFor[TRequest any](limit Limit) RateLimitOption {
+	return func(p *RateLimit) {
+		var request TRequest
+		p.limits[reflect.TypeOf(request)] = limit
+	}
+}
+
+// WithRateLimitMode sets what happens to requests exceeding their limit. Defaults to RateLimitReject.
+func WithRateLimitMode(mode RateLimitMode) RateLimitOption {
+	return func(p *RateLimit) {
+		p.mode = mode
+	}
+}
+
+// WithClock replaces the clock used to refill buckets and wait for tokens.
+func WithClock(clock Clock) RateLimitOption {
+	return func(p *RateLimit) {
+		if clock != nil {
+			p.clock = clock
+		}
+	}
+}
+
+type bucketKey struct {
+	requestType reflect.Type
+	key         string
+}
+
+// bucketSweepInterval is how often buckets that have refilled completely are removed.
+const bucketSweepInterval = time.Minute
+
+// tokenBucket holds the tokens of a single bucket. Tokens may go negative when
+// requests reserve tokens they wait for.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	rate   float64
+	burst  float64
+}
+
+// full reports whether the bucket has refilled completely, making it indistinguishable from
+// a new bucket.
+func (b *tokenBucket) full(now time.Time) bool {
+	return b.tokens+now.Sub(b.last).Seconds()*b.rate >= b.burst
+}
+
+// RateLimit is a pipeline that limits the rate of requests using token buckets. Each request
+// type has its own bucket, or one bucket per key for requests implementing RateLimitKeyer.
+// Buckets that have refilled completely are removed, so keys seen once do not accumulate.
+type RateLimit struct {
+	BasePipeline
+	defaultLimit Limit
+	limits       map[reflect.Type]Limit
+	mode         RateLimitMode
+	clock        Clock
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*tokenBucket
+	lastSweep time.Time
+	sweepSize int
+}
+
+// NewRateLimit creates a RateLimit pipeline.
+//
+// Parameters:
+//   - defaultLimit: The limit of request types without a specific one, a zero Limit leaves them unlimited
+//   - opts: Per request type limits and behavior options
+//
+// Returns:
+//   - *RateLimit: The created pipeline
+func NewRateLimit(defaultLimit Limit, opts ...RateLimitOption) *RateLimit {
+	p := &RateLimit{
+		defaultLimit: defaultLimit,
+		limits:       make(map[reflect.Type]Limit),
+		clock:        realClock{},
+		buckets:      make(map[bucketKey]*tokenBucket),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Handle takes a token from the bucket of the request and calls the next pipeline.
+//
+// Parameters:
+//   - request: The request object to process
+//   - params: Optional additional parameters passed to the pipeline
+//
+// Returns:
+//   - any: The response from the next pipeline, or nil if the request is rate limited
+//   - error: A *RateLimitedError, the context error while waiting, or an error from the next pipeline
+func (p *RateLimit) Handle(request any, params ...any) (any, error) {
+	requestType := reflect.TypeOf(request)
+	limit, ok := p.limits[requestType]
+	if !ok {
+		limit = p.defaultLimit
+	}
+	if limit.Rate <= 0 {
+		return p.Next().Handle(request, params...)
+	}
+
+	key := bucketKey{requestType: requestType}
+	if keyer, ok := request.(RateLimitKeyer); ok {
+		key.key = keyer.RateLimitKey()
+	}
+
+	wait, allowed := p.take(key, limit)
+	if !allowed {
+		return nil, &RateLimitedError{RequestType: requestType.String(), Key: key.key, RetryAfter: wait}
+	}
+	if wait > 0 {
+		ctx := contexts.From(params...)
+		select {
+		case <-p.clock.After(wait):
+		case <-ctx.Done():
+			p.refund(key)
+			return nil, ctx.Err()
+		}
+	}
+	return p.Next().Handle(request, params...)
+}
+
+// take removes a token from the bucket. In reject mode it reports the time until a token is
+// available when there is none; in wait mode it reserves the token and reports how long to wait.
+func (p *RateLimit) take(key bucketKey, limit Limit) (time.Duration, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	burst := float64(max(limit.Burst, 1))
+	now := p.clock.Now()
+	p.sweep(now)
+	bucket, ok := p.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: burst, last: now, rate: limit.Rate, burst: burst}
+		p.buckets[key] = bucket
+	}
+	bucket.tokens = min(burst, bucket.tokens+now.Sub(bucket.last).Seconds()*limit.Rate)
+	bucket.last = now
+
+	missing := 1 - bucket.tokens
+	wait := time.Duration(missing / limit.Rate * float64(time.Second))
+	if missing > 0 && p.mode == RateLimitReject {
+		return wait, false
+	}
+	bucket.tokens--
+	return max(wait, 0), true
+}
+
+// Buckets returns the number of token buckets currently held.
+func (p *RateLimit) Buckets() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.buckets)
+}
+
+// sweep removes the buckets that have refilled completely, once per bucketSweepInterval or
+// whenever the number of buckets has doubled since the previous sweep. The caller must hold the lock.
+func (p *RateLimit) sweep(now time.Time) {
+	if now.Sub(p.lastSweep) < bucketSweepInterval && len(p.buckets) < 2*max(p.sweepSize, 64) {
+		return
+	}
+	for key, bucket := range p.buckets {
+		if bucket.full(now) {
+			delete(p.buckets, key)
+		}
+	}
+	p.lastSweep = now
+	p.sweepSize = len(p.buckets)
+}
+
+// refund returns a reserved token to the bucket when its request stops waiting.
+func (p *RateLimit) refund(key bucketKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if bucket, ok := p.buckets[key]; ok {
+		bucket.tokens++
+	}
+}
@@ -0,0 +1,57 @@
+package pipeline
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var _ interfaces.NotificationPipeline = (*Recovery)(nil)
+
+// Recovery is a notification pipeline that recovers panics raised by subscribers, so that a
+// failing subscriber does not crash the process. Register it first with
+// godiator.RegisterNotificationPipeline to protect every subscriber.
+type Recovery struct {
+	onPanic func(event any, recovered any, stack []byte)
+}
+
+// NewRecovery creates a Recovery notification pipeline.
+//
+// Parameters:
+//   - onPanic: Called with the event, the recovered value and the stack trace of every panic,
+//     nil logs them with slog.Default()
+//
+// Returns:
+//   - *Recovery: The created pipeline
+func NewRecovery(onPanic func(event any, recovered any, stack []byte)) *Recovery {
+	if onPanic == nil {
+		onPanic = func(event any, recovered any, stack []byte) {
+			slog.Error("subscriber panicked",
+				slog.String("event_type", fmt.Sprintf("%T", event)),
+				slog.Any("panic", recovered),
+				slog.String("stack", string(stack)))
+		}
+	}
+	return &Recovery{onPanic: onPanic}
+}
+
+// Handle calls the rest of the chain and recovers its panics.
+//
+// Parameters:
+//   - event: The published event
+//   - next: The rest of the chain
+//   - params: Optional additional parameters passed to the subscriber
+//
+// Returns:
+//   - error: The error returned by the rest of the chain, or nil once a panic is recovered
+func (p *Recovery) Handle(event any, next func(event any, params ...any) error, params ...any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.onPanic(event, r, debug.Stack())
+			err = nil
+		}
+	}()
+	return next(event, params...)
+}
@@ -0,0 +1,57 @@
+package pipeline
+
+import (
+	"context"
+	"database/sql"
+)
+
+var _ TxManager = (*SQLTxManager)(nil)
+
+// SQLTxManager is a TxManager opening database/sql transactions.
+type SQLTxManager struct {
+	db      *sql.DB
+	options *sql.TxOptions
+}
+
+// NewSQLTxManager creates an SQLTxManager for the database.
+//
+// Parameters:
+//   - db: The database to open transactions on
+//   - options: The transaction options, nil for the driver defaults
+//
+// Returns:
+//   - *SQLTxManager: The created manager
+func NewSQLTxManager(db *sql.DB, options *sql.TxOptions) *SQLTxManager {
+	return &SQLTxManager{db: db, options: options}
+}
+
+// Begin opens a transaction. It is rolled back by database/sql if ctx is cancelled first.
+//
+// Parameters:
+//   - ctx: The context of the request
+//
+// Returns:
+//   - Tx: The opened *sql.Tx
+//   - error: An error if the transaction cannot be opened
+func (m *SQLTxManager) Begin(ctx context.Context) (Tx, error) {
+	tx, err := m.db.BeginTx(ctx, m.options)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// SQLTxFromContext returns the *sql.Tx opened by the Transaction pipeline with an SQLTxManager.
+//
+// Example:
+//
+//	tx, _ := pipeline.SQLTxFromContext(godiator.ContextFrom(params...))
+//	_, err := tx.ExecContext(ctx, "INSERT INTO orders (id) VALUES (?)", cmd.ID)
+func SQLTxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := TxFromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	sqlTx, ok := tx.(*sql.Tx)
+	return sqlTx, ok
+}
@@ -0,0 +1,146 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/baranius/godiator/core/contexts"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var _ interfaces.Pipeline = (*Timeout)(nil)
+
+// ErrTimeout is matched by every *TimeoutError with errors.Is.
+var ErrTimeout = errors.New("request timed out")
+
+// TimeoutError is returned when the chain below a Timeout pipeline does not complete in time.
+type TimeoutError struct {
+	RequestType string
+	Timeout     time.Duration
+}
+
+// Error implements the error interface.
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf(`request "%s" timed out after %s`, e.RequestType, e.Timeout)
+}
+
+// Is reports whether the target is ErrTimeout.
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// Timeouter can be implemented by requests to choose their own timeout.
+// It takes precedence over the timeouts configured on the pipeline.
+type Timeouter interface {
+	Timeout() time.Duration
+}
+
+// TimeoutOption configures a Timeout pipeline.
+type TimeoutOption func(*Timeout)
+
+// TimeoutFor sets the timeout of a specific request type, overriding the default.
+//
+// Type parameters:
+//   - TRequest: The request type the timeout applies to
+//
+// Parameters:
+//   - timeout: The timeout, zero disables the timeout for the request type
+func TimeoutFor[TRequest any](timeout time.Duration) TimeoutOption {
+	return func(p *Timeout) {
+		var request TRequest
+		p.timeouts[reflect.TypeOf(request)] = timeout
+	}
+}
+
+// Timeout is a pipeline that enforces a deadline on the chain below it. The context found in
+// params (see godiator.ContextFrom) is replaced by one that is cancelled when the deadline
+// expires, so handlers can stop their work. If the chain does not complete in time, Handle
+// returns a *TimeoutError without waiting for it; the chain still running is no longer tracked
+// by godiator.Shutdown, which may close the handlers before it completes.
+type Timeout struct {
+	BasePipeline
+	defaultTimeout time.Duration
+	timeouts       map[reflect.Type]time.Duration
+}
+
+// NewTimeout creates a Timeout pipeline.
+//
+// Parameters:
+//   - defaultTimeout: The timeout of request types without a specific one, zero disables it
+//   - opts: Per request type timeouts
+//
+// Returns:
+//   - *Timeout: The created pipeline
+func NewTimeout(defaultTimeout time.Duration, opts ...TimeoutOption) *Timeout {
+	p := &Timeout{
+		defaultTimeout: defaultTimeout,
+		timeouts:       make(map[reflect.Type]time.Duration),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type timeoutResult struct {
+	response any
+	err      error
+	panicked any
+}
+
+// Handle calls the next pipeline with a deadline.
+//
+// Parameters:
+//   - request: The request object to process
+//   - params: Optional additional parameters passed to the pipeline
+//
+// Returns:
+//   - any: The response from the next pipeline, or nil if the deadline expired
+//   - error: A *TimeoutError if the deadline expired, the context error if the
+//     caller's context was cancelled, or an error from the next pipeline
+func (p *Timeout) Handle(request any, params ...any) (any, error) {
+	timeout := p.timeoutFor(request)
+	if timeout <= 0 {
+		return p.Next().Handle(request, params...)
+	}
+
+	ctx, cancel := context.WithTimeout(contexts.From(params...), timeout)
+	defer cancel()
+	params = contexts.With(ctx, params...)
+
+	done := make(chan timeoutResult, 1)
+	go func() {
+		var result timeoutResult
+		defer func() {
+			result.panicked = recover()
+			done <- result
+		}()
+		result.response, result.err = p.Next().Handle(request, params...)
+	}()
+
+	select {
+	case result := <-done:
+		if result.panicked != nil {
+			panic(result.panicked)
+		}
+		return result.response, result.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, &TimeoutError{RequestType: reflect.TypeOf(request).String(), Timeout: timeout}
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func (p *Timeout) timeoutFor(request any) time.Duration {
+	if timeouter, ok := request.(Timeouter); ok {
+		return timeouter.Timeout()
+	}
+	if timeout, ok := p.timeouts[reflect.TypeOf(request)]; ok {
+		return timeout
+	}
+	return p.defaultTimeout
+}
@@ -0,0 +1,116 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/baranius/godiator/core/contexts"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var _ interfaces.Pipeline = (*Transaction)(nil)
+
+// Tx is a transaction opened by a TxManager.
+type Tx interface {
+	Commit() error
+	Rollback() error
+}
+
+// TxManager opens transactions for the Transaction pipeline.
+type TxManager interface {
+	Begin(ctx context.Context) (Tx, error)
+}
+
+// ReadOnly can be implemented by requests that do not need a transaction, such as queries.
+type ReadOnly interface {
+	ReadOnly() bool
+}
+
+type txKey struct{}
+
+// ContextWithTx returns a copy of ctx carrying the transaction.
+func ContextWithTx(ctx context.Context, tx Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the transaction opened by the Transaction pipeline for the request.
+// Handlers retrieve the context with godiator.ContextFrom.
+//
+// Example:
+//
+//	func (h *PlaceOrderHandler) Handle(cmd PlaceOrderCommand, params ...any) (PlaceOrderResponse, error) {
+//	    tx, _ := pipeline.TxFromContext(godiator.ContextFrom(params...))
+//	    // Use tx...
+//	}
+func TxFromContext(ctx context.Context) (Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(Tx)
+	return tx, ok
+}
+
+// Transaction is a pipeline that runs each request in a unit of work. A transaction is opened
+// before the request reaches the handler and exposed through the context passed in params.
+// It is committed if the chain succeeds, and rolled back if it returns an error or panics.
+// ReadOnly requests, and requests sent while a transaction is already in the context, run
+// without opening a new transaction.
+type Transaction struct {
+	BasePipeline
+	manager TxManager
+}
+
+// NewTransaction creates a Transaction pipeline opening transactions with the manager.
+//
+// Parameters:
+//   - manager: The manager opening transactions
+//
+// Returns:
+//   - *Transaction: The created pipeline
+func NewTransaction(manager TxManager) *Transaction {
+	return &Transaction{manager: manager}
+}
+
+// Handle calls the next pipeline inside a transaction.
+//
+// Parameters:
+//   - request: The request object to process
+//   - params: Optional additional parameters passed to the pipeline
+//
+// Returns:
+//   - any: The response from the next pipeline, or nil if the transaction cannot be committed
+//   - error: An error from the next pipeline or from the transaction
+func (p *Transaction) Handle(request any, params ...any) (response any, err error) {
+	if readOnly, ok := request.(ReadOnly); ok && readOnly.ReadOnly() {
+		return p.Next().Handle(request, params...)
+	}
+	ctx := contexts.From(params...)
+	if _, ok := TxFromContext(ctx); ok {
+		return p.Next().Handle(request, params...)
+	}
+
+	tx, err := p.manager.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+
+	completed := false
+	defer func() {
+		// Roll back on panic, the panic keeps propagating
+		if !completed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	response, err = p.Next().Handle(request, contexts.With(ContextWithTx(ctx, tx), params...)...)
+	completed = true
+
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return response, errors.Join(err, fmt.Errorf("rolling back transaction: %w", rollbackErr))
+		}
+		return response, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	return response, nil
+}
@@ -0,0 +1,133 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var _ interfaces.Pipeline = (*Validation)(nil)
+
+// Validatable can be implemented by requests that validate themselves, with a value or a
+// pointer receiver. Returning a *ValidationError or FieldError reports per-field details.
+type Validatable interface {
+	Validate() error
+}
+
+// FieldError describes why a single field of a request is invalid.
+// An empty Field means the error applies to the request as a whole.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// Error implements the error interface.
+func (e FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + " " + e.Message
+}
+
+// ValidationError aggregates every field error found for a request.
+type ValidationError struct {
+	RequestType string
+	Fields      []FieldError
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Error())
+	}
+	return fmt.Sprintf(`validation failed for "%s": %s`, e.RequestType, strings.Join(messages, "; "))
+}
+
+// Validation is a pipeline that validates requests before they reach the handler.
+// A request is checked against its `validate` struct tags (see ValidateStruct), its own
+// Validate method if it implements Validatable, and every validator registered with
+// godiator.RegisterValidator. All failures are aggregated into a single *ValidationError.
+type Validation struct {
+	BasePipeline
+}
+
+// NewValidation creates a Validation pipeline.
+//
+// Returns:
+//   - *Validation: The created pipeline
+func NewValidation() *Validation {
+	return &Validation{}
+}
+
+// Handle validates the request and calls the next pipeline only if it is valid.
+//
+// Parameters:
+//   - request: The request object to process
+//   - params: Optional additional parameters passed to the pipeline
+//
+// Returns:
+//   - any: The response from the next pipeline, or nil if the request is invalid
+//   - error: A *ValidationError if the request is invalid, an error wrapping ErrInvalidValidationRule
+//     if a `validate` tag of the request is misconfigured, or an error from the next pipeline
+func (p *Validation) Handle(request any, params ...any) (any, error) {
+	fields, err := ValidateStruct(request)
+	if err != nil {
+		return nil, fmt.Errorf(`validating "%s": %w`, reflect.TypeOf(request).String(), err)
+	}
+
+	if validatable, ok := asValidatable(request); ok {
+		fields = appendFieldErrors(fields, validatable.Validate())
+	}
+	for _, validator := range core.GetValidators(request) {
+		fields = appendFieldErrors(fields, validator.Validate(request))
+	}
+
+	if len(fields) > 0 {
+		return nil, &ValidationError{RequestType: reflect.TypeOf(request).String(), Fields: fields}
+	}
+	return p.Next().Handle(request, params...)
+}
+
+// asValidatable returns the request as a Validatable, calling Validate on a copy of the request
+// when it is implemented with a pointer receiver but the request is sent by value.
+func asValidatable(request any) (Validatable, bool) {
+	if validatable, ok := request.(Validatable); ok {
+		return validatable, true
+	}
+	requestType := reflect.TypeOf(request)
+	if requestType == nil || !reflect.PointerTo(requestType).Implements(reflect.TypeFor[Validatable]()) {
+		return nil, false
+	}
+	pointer := reflect.New(requestType)
+	pointer.Elem().Set(reflect.ValueOf(request))
+	return pointer.Interface().(Validatable), true
+}
+
+// appendFieldErrors flattens the error returned by a validator into field errors.
+func appendFieldErrors(fields []FieldError, err error) []FieldError {
+	if err == nil {
+		return fields
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			fields = appendFieldErrors(fields, e)
+		}
+		return fields
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return append(fields, validationErr.Fields...)
+	}
+	var fieldErr FieldError
+	if errors.As(err, &fieldErr) {
+		return append(fields, fieldErr)
+	}
+	return append(fields, FieldError{Message: err.Error()})
+}
@@ -0,0 +1,210 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// validateTag is the struct tag holding the validation rules of a field.
+const validateTag = "validate"
+
+// compiledPatterns caches the regular expressions used by regex rules.
+var compiledPatterns sync.Map
+
+// ErrInvalidValidationRule is returned by ValidateStruct, and by the Validation pipeline, when
+// a `validate` tag is misconfigured: the rule is unknown, its parameter is invalid, or it does
+// not apply to the type of the field.
+var ErrInvalidValidationRule = errors.New("invalid validation rule")
+
+// ValidateStruct checks the exported fields of a struct against the rules declared in their
+// `validate` tag and returns a FieldError for every violation, or an error wrapping
+// ErrInvalidValidationRule if a tag is misconfigured. Nested structs are validated
+// recursively, except for pointers back to a struct already being validated, so self-referencing
+// values are safe. Values that are not structs, or pointers to structs, have no rules.
+//
+// Supported rules, separated by commas:
+//   - required: the field must not be its zero value
+//   - min=N: numbers must be at least N; strings, slices and maps must have at least N elements
+//   - max=N: numbers must be at most N; strings, slices and maps must have at most N elements
+//   - regex=PATTERN: strings must match PATTERN. It must be the last rule, as the pattern may contain commas
+//
+// Example:
+//
+//	type CreateUserRequest struct {
+//	    Name  string `validate:"required,max=50"`
+//	    Age   int    `validate:"min=18"`
+//	    Email string `validate:"required,regex=^[^@]+@[^@]+$"`
+//	}
+func ValidateStruct(v any) ([]FieldError, error) {
+	value := reflect.ValueOf(v)
+	path := make(map[visitedPointer]bool)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil, nil
+		}
+		path[visitedPointer{value.Type(), value.Pointer()}] = true
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	return validateFields(value, "", path)
+}
+
+// visitedPointer identifies a pointer followed on the way to the struct being validated.
+type visitedPointer struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+func validateFields(value reflect.Value, prefix string, path map[visitedPointer]bool) ([]FieldError, error) {
+	var fields []FieldError
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := prefix + field.Name
+		fieldValue := value.Field(i)
+
+		if rules, ok := field.Tag.Lookup(validateTag); ok {
+			violations, err := validateRules(name, fieldValue, rules)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, violations...)
+		}
+
+		nested := fieldValue
+		if nested.Kind() == reflect.Pointer && !nested.IsNil() {
+			visit := visitedPointer{nested.Type(), nested.Pointer()}
+			if path[visit] {
+				// The pointer leads back to a struct being validated
+				continue
+			}
+			if nested.Elem().Kind() == reflect.Struct {
+				path[visit] = true
+				violations, err := validateFields(nested.Elem(), name+".", path)
+				delete(path, visit)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, violations...)
+			}
+			continue
+		}
+		if nested.Kind() == reflect.Struct {
+			violations, err := validateFields(nested, name+".", path)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, violations...)
+		}
+	}
+	return fields, nil
+}
+
+func validateRules(name string, value reflect.Value, rules string) ([]FieldError, error) {
+	var fields []FieldError
+	for rules != "" {
+		rule, rest, _ := strings.Cut(rules, ",")
+		ruleName, param, _ := strings.Cut(rule, "=")
+		if ruleName == "regex" {
+			// The pattern consumes the rest of the tag
+			param, rest = strings.TrimPrefix(rules, "regex="), ""
+		}
+		rules = rest
+
+		message, err := checkRule(value, strings.TrimSpace(ruleName), param)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %s %s", ErrInvalidValidationRule, name, err.Error())
+		}
+		if message != "" {
+			fields = append(fields, FieldError{Field: name, Message: message})
+		}
+	}
+	return fields, nil
+}
+
+// checkRule returns a message describing the violation, or an empty string if the rule holds.
+// It returns an error if the rule cannot be checked against the field.
+func checkRule(value reflect.Value, rule string, param string) (string, error) {
+	switch rule {
+	case "":
+		return "", nil
+	case "required":
+		if value.IsZero() {
+			return "is required", nil
+		}
+		return "", nil
+	case "min", "max":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return "", fmt.Errorf("has invalid %s rule %q", rule, param)
+		}
+		return checkBound(value, rule, limit)
+	case "regex":
+		if value.Kind() != reflect.String {
+			return "", fmt.Errorf("has regex rule on a %s field", value.Kind())
+		}
+		pattern, err := compilePattern(param)
+		if err != nil {
+			return "", fmt.Errorf("has invalid regex rule %q", param)
+		}
+		if !pattern.MatchString(value.String()) {
+			return fmt.Sprintf("must match %q", param), nil
+		}
+		return "", nil
+	default:
+		return "", fmt.Errorf("has unknown rule %q", rule)
+	}
+}
+
+func checkBound(value reflect.Value, rule string, limit float64) (string, error) {
+	var actual float64
+	subject := "must be"
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		actual = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		actual = value.Float()
+	case reflect.String:
+		actual = float64(utf8.RuneCountInString(value.String()))
+		subject = "length must be"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		actual = float64(value.Len())
+		subject = "length must be"
+	default:
+		return "", fmt.Errorf("has %s rule on a %s field", rule, value.Kind())
+	}
+
+	limitText := strconv.FormatFloat(limit, 'f', -1, 64)
+	if rule == "min" && actual < limit {
+		return fmt.Sprintf("%s at least %s", subject, limitText), nil
+	}
+	if rule == "max" && actual > limit {
+		return fmt.Sprintf("%s at most %s", subject, limitText), nil
+	}
+	return "", nil
+}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := compiledPatterns.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	compiledPatterns.Store(pattern, compiled)
+	return compiled, nil
+}
@@ -0,0 +1,109 @@
+package godiator
+
+import (
+	"fmt"
+
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+// PipelineOption configures the name and position of a registered pipeline.
+type PipelineOption func(*pipelineOptions)
+
+type pipelineOptions struct {
+	entry     core.PipelineEntry
+	placement core.PipelinePlacement
+}
+
+// WithPipelineName names the pipeline so that it can be referenced by InsertBefore,
+// InsertAfter and UnregisterPipeline. Names must be unique.
+func WithPipelineName(name string) PipelineOption {
+	return func(o *pipelineOptions) {
+		o.entry.Name = name
+	}
+}
+
+// WithPipelineOrder sets the order of the pipeline. Pipelines with a lower order run first,
+// pipelines with the same order run in registration order. Defaults to 0.
+func WithPipelineOrder(order int) PipelineOption {
+	return func(o *pipelineOptions) {
+		o.entry.Order = order
+	}
+}
+
+// InsertBefore places the pipeline right before the named pipeline, taking its order.
+func InsertBefore(name string) PipelineOption {
+	return func(o *pipelineOptions) {
+		o.placement = core.PipelinePlacement{Before: name}
+	}
+}
+
+// InsertAfter places the pipeline right after the named pipeline, taking its order.
+func InsertAfter(name string) PipelineOption {
+	return func(o *pipelineOptions) {
+		o.placement = core.PipelinePlacement{After: name}
+	}
+}
+
+// PipelineInfo describes a registered pipeline.
+type PipelineInfo struct {
+	Name  string
+	Order int
+	Type  string
+}
+
+// describedPipeline is implemented by pipelines adapting another value, to report its type.
+type describedPipeline interface {
+	pipelineType() string
+}
+
+func insertPipeline(pipeline interfaces.Pipeline, opts ...PipelineOption) error {
+	var options pipelineOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	options.entry.Pipeline = pipeline
+	return core.InsertPipeline(options.entry, options.placement)
+}
+
+// UnregisterPipeline removes the pipeline registered with the name.
+//
+// Parameters:
+//   - name: The name given with WithPipelineName
+//
+// Returns:
+//   - bool: Indicates whether the pipeline was found
+//
+// Example:
+//
+//	godiator.UnregisterPipeline("logging")
+func UnregisterPipeline(name string) bool {
+	return core.RemovePipeline(name)
+}
+
+// Pipelines returns the registered pipelines in the order they are executed.
+//
+// Returns:
+//   - []PipelineInfo: The registered pipelines
+//
+// Example:
+//
+//	for _, p := range godiator.Pipelines() {
+//	    fmt.Printf("%d %s (%s)\n", p.Order, p.Name, p.Type)
+//	}
+func Pipelines() []PipelineInfo {
+	entries := core.ListPipelines()
+	infos := make([]PipelineInfo, 0, len(entries))
+	for _, entry := range entries {
+		infos = append(infos, PipelineInfo{Name: entry.Name, Order: entry.Order, Type: pipelineTypeName(entry.Pipeline)})
+	}
+	return infos
+}
+
+// pipelineTypeName returns the type of the pipeline, or of the value it adapts.
+func pipelineTypeName(pipeline interfaces.Pipeline) string {
+	if described, ok := pipeline.(describedPipeline); ok {
+		return described.pipelineType()
+	}
+	return fmt.Sprintf("%T", pipeline)
+}
@@ -0,0 +1,29 @@
+package godiator
+
+import (
+	"context"
+
+	"github.com/baranius/godiator/core/contexts"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+// ContextWithPrincipal returns a copy of ctx carrying the principal, to be passed to Send
+// so that the authorization pipeline can evaluate it.
+//
+// Example:
+//
+//	ctx := godiator.ContextWithPrincipal(r.Context(), currentUser)
+//	godiator.Send[DeleteOrderCommand, DeleteOrderResponse](cmd, ctx)
+func ContextWithPrincipal(ctx context.Context, principal interfaces.Principal) context.Context {
+	return contexts.WithPrincipal(ctx, principal)
+}
+
+// PrincipalFrom returns the principal of a request, taken from the context found in params
+// (see ContextWithPrincipal). Params implementing Principal are not taken for the principal.
+//
+// Returns:
+//   - interfaces.Principal: The principal
+//   - bool: Indicates whether a principal was found
+func PrincipalFrom(params ...any) (interfaces.Principal, bool) {
+	return contexts.PrincipalFrom(params...)
+}
@@ -0,0 +1,40 @@
+package godiator
+
+import (
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+// handleWithProcessors runs the pre-processors, the handler and the post-processors of a request.
+// It is the last ring of the chain built by Send.
+func handleWithProcessors[TRequest any, TResponse any](handler interfaces.Handler[any, any], request TRequest, params ...any) (any, error) {
+	for _, processor := range core.GetPreProcessors[TRequest]() {
+		if err := processor.Process(&request, params...); err != nil {
+			return nil, err
+		}
+	}
+
+	response, err := handler.Handle(request, params...)
+	if err != nil {
+		return response, err
+	}
+
+	typedResponse, _ := response.(TResponse)
+	for _, processor := range core.GetPostProcessors[TRequest, TResponse]() {
+		if err := processor.Process(request, typedResponse, params...); err != nil {
+			return response, err
+		}
+	}
+	return response, nil
+}
+
+// handleException gives the registered exception handlers a chance to turn the error
+// returned while sending a request into a fallback response.
+func handleException[TRequest any, TResponse any](request TRequest, response TResponse, err error, params ...any) (TResponse, error) {
+	for _, handler := range core.GetExceptionHandlers[TRequest, TResponse]() {
+		if fallback, handled := handler(request, err, params...); handled {
+			return fallback, nil
+		}
+	}
+	return response, err
+}
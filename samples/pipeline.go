@@ -25,7 +25,7 @@ type (
 // Check the pipeline interface (https://github.com/baranius/godiator/blob/master/pipeline/pipeline.go) for more details.
 func (p *LoggingPipeline) Handle(request any, params ...any) (any, error) {
 	// Call the next pipeline in the chain.
-	response, err := p.Next().Handle(request, params)
+	response, err := p.Next().Handle(request, params...)
 
 	// If an error occurs, return it.
 	if err != nil {
@@ -0,0 +1,277 @@
+package godiator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/baranius/godiator/core/contexts"
+	"github.com/baranius/godiator/internal/testhooks"
+)
+
+// ErrShuttingDown is returned by Send and Publish once Shutdown has been called.
+var ErrShuttingDown = errors.New("mediator is shutting down")
+
+// AbandonedWork describes an operation still in flight when Shutdown gave up waiting.
+type AbandonedWork struct {
+	// Kind is "send", "publish", "subscriber", or "singleflight" and "batch" for the shared
+	// executions of handlers registered WithSingleflight and with RegisterBatchHandler.
+	Kind        string
+	RequestType string
+	// Subscriber is the name of the subscriber, empty unless Kind is "subscriber".
+	Subscriber string
+	Since      time.Time
+}
+
+// ShutdownError is returned by Shutdown when its context expires before in-flight
+// operations complete. It wraps the error of the context.
+type ShutdownError struct {
+	Abandoned []AbandonedWork
+	Err       error
+}
+
+// Error implements the error interface.
+func (e *ShutdownError) Error() string {
+	return fmt.Sprintf("shutdown abandoned %d in-flight operations: %v", len(e.Abandoned), e.Err)
+}
+
+// Unwrap returns the error of the context.
+func (e *ShutdownError) Unwrap() error {
+	return e.Err
+}
+
+// workTracker tracks the operations in flight, so that Shutdown can wait for them.
+type workTracker struct {
+	mu      sync.Mutex
+	closing bool
+	nextID  uint64
+	active  map[uint64]AbandonedWork
+	idle    chan struct{}
+}
+
+var inFlight = &workTracker{active: make(map[uint64]AbandonedWork), idle: make(chan struct{})}
+
+// trackedKey marks the context passed to the handlers and subscribers of tracked operations.
+type trackedKey struct{}
+
+func init() {
+	testhooks.ResetShutdown = func() {
+		inFlight.reopen()
+		closeOnce = sync.Once{}
+	}
+}
+
+// begin tracks a new operation, unless the mediator is shutting down.
+func (t *workTracker) begin(work AbandonedWork) (uint64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closing {
+		return 0, false
+	}
+	return t.track(work), true
+}
+
+// beginCall tracks a Send or Publish and returns its params with a context marked as tracked.
+// Calls made with a marked context come from the handlers and subscribers of operations in
+// flight, so they are tracked even if the mediator is shutting down, for the drain to complete
+// them; other calls are rejected once Shutdown has been called.
+func (t *workTracker) beginCall(work AbandonedWork, params []any) (uint64, []any, bool) {
+	ctx := contexts.From(params...)
+	if ctx.Value(trackedKey{}) != nil {
+		return t.add(work), params, true
+	}
+	id, ok := t.begin(work)
+	if !ok {
+		return 0, params, false
+	}
+	return id, contexts.With(context.WithValue(ctx, trackedKey{}, true), params...), true
+}
+
+// add tracks an operation started by an operation in flight, even if the mediator is shutting down.
+func (t *workTracker) add(work AbandonedWork) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.track(work)
+}
+
+// track records the operation. The caller must hold the lock.
+func (t *workTracker) track(work AbandonedWork) uint64 {
+	t.nextID++
+	work.Since = time.Now()
+	t.active[t.nextID] = work
+	return t.nextID
+}
+
+// end stops tracking an operation, signalling Shutdown once the last one completes.
+func (t *workTracker) end(id uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.active, id)
+	if t.closing && len(t.active) == 0 {
+		t.signalIdle()
+	}
+}
+
+// close stops accepting new operations and returns a channel closed once none is in flight.
+func (t *workTracker) close() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closing = true
+	if len(t.active) == 0 {
+		t.signalIdle()
+	}
+	return t.idle
+}
+
+// signalIdle closes the idle channel once. The caller must hold the lock.
+func (t *workTracker) signalIdle() {
+	select {
+	case <-t.idle:
+	default:
+		close(t.idle)
+	}
+}
+
+// reopen accepts new operations again after close. Operations still tracked are forgotten.
+func (t *workTracker) reopen() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closing = false
+	t.active = make(map[uint64]AbandonedWork)
+	t.idle = make(chan struct{})
+}
+
+// abandoned returns the operations in flight, oldest first.
+func (t *workTracker) abandoned() []AbandonedWork {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	works := make([]AbandonedWork, 0, len(t.active))
+	for _, id := range slices.Sorted(maps.Keys(t.active)) {
+		works = append(works, t.active[id])
+	}
+	return works
+}
+
+var (
+	closersMu         sync.Mutex
+	handlerClosers    = make(map[reflect.Type]io.Closer)
+	subscriberClosers = make(map[reflect.Type][]io.Closer)
+	closeOnce         sync.Once
+)
+
+// setHandlerCloser records the handler of the request type to be closed on shutdown if it
+// implements io.Closer. A nil handler forgets the previous one.
+func setHandlerCloser[TRequest any](handler any) {
+	closersMu.Lock()
+	defer closersMu.Unlock()
+
+	requestType := reflect.TypeFor[TRequest]()
+	if closer, ok := handler.(io.Closer); ok {
+		handlerClosers[requestType] = closer
+	} else {
+		delete(handlerClosers, requestType)
+	}
+}
+
+// addSubscriberCloser records a subscriber of the request type to be closed on shutdown if it
+// implements io.Closer.
+func addSubscriberCloser[TRequest any](subscriber any) {
+	closersMu.Lock()
+	defer closersMu.Unlock()
+
+	if closer, ok := subscriber.(io.Closer); ok {
+		requestType := reflect.TypeFor[TRequest]()
+		subscriberClosers[requestType] = append(subscriberClosers[requestType], closer)
+	}
+}
+
+// removeSubscriberClosers forgets the subscribers of the request type.
+func removeSubscriberClosers[TRequest any]() {
+	closersMu.Lock()
+	defer closersMu.Unlock()
+
+	delete(subscriberClosers, reflect.TypeFor[TRequest]())
+}
+
+// closeRegistered closes every registered handler and subscriber implementing io.Closer once,
+// even if it is registered for several request types.
+func closeRegistered() error {
+	closersMu.Lock()
+	defer closersMu.Unlock()
+
+	closers := make([]io.Closer, 0, len(handlerClosers))
+	for _, closer := range handlerClosers {
+		closers = append(closers, closer)
+	}
+	for _, subscribers := range subscriberClosers {
+		closers = append(closers, subscribers...)
+	}
+
+	var errs []error
+	closed := make(map[io.Closer]bool)
+	for _, closer := range closers {
+		if reflect.TypeOf(closer).Comparable() {
+			if closed[closer] {
+				continue
+			}
+			closed[closer] = true
+		}
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing %T: %w", closer, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown stops the mediator gracefully. New calls to Send and Publish fail with
+// ErrShuttingDown, then Shutdown waits for the requests and subscribers in flight to complete,
+// or for the context to expire. Registered handlers and subscribers implementing io.Closer
+// are closed afterwards, whether or not everything completed. The mediator cannot be restarted.
+//
+// Send and Publish calls made by the handlers and subscribers still running while Shutdown
+// waits are accepted and waited for, as long as they pass on the context the handler or
+// subscriber received. Executions shared by the callers of handlers registered WithSingleflight
+// or with RegisterBatchHandler are waited for too. Handlers left running by the Timeout
+// pipeline after their request timed out are not: they may still be running when the closers
+// are called.
+//
+// Parameters:
+//   - ctx: The context bounding how long to wait for operations in flight
+//
+// Returns:
+//   - error: A *ShutdownError listing the operations abandoned when the context expires,
+//     joined with the errors returned by Close
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	if err := godiator.Shutdown(ctx); err != nil {
+//	    log.Printf("shutdown: %v", err)
+//	}
+func Shutdown(ctx context.Context) error {
+	var shutdownErr error
+	select {
+	case <-inFlight.close():
+	case <-ctx.Done():
+		shutdownErr = &ShutdownError{Abandoned: inFlight.abandoned(), Err: ctx.Err()}
+	}
+
+	var closeErr error
+	closeOnce.Do(func() {
+		closeErr = closeRegistered()
+	})
+	return errors.Join(shutdownErr, closeErr)
+}
@@ -0,0 +1,103 @@
+package godiator
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/baranius/godiator/core/contexts"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+// SingleflightKeyer can be implemented by requests registered WithSingleflight to control
+// which concurrent requests are considered identical.
+//
+// Example:
+//
+//	func (r GetProductRequest) SingleflightKey() string {
+//	    return r.SKU
+//	}
+type SingleflightKeyer interface {
+	SingleflightKey() string
+}
+
+// singleflightCall is an in-flight or completed handler execution shared by its callers.
+type singleflightCall[TResponse any] struct {
+	done     chan struct{}
+	response TResponse
+	err      error
+}
+
+// singleflightHandler runs at most one handler execution per key at a time.
+type singleflightHandler[TRequest any, TResponse any] struct {
+	handler interfaces.Handler[TRequest, TResponse]
+
+	mu    sync.Mutex
+	calls map[any]*singleflightCall[TResponse]
+}
+
+func newSingleflightHandler[TRequest any, TResponse any](handler interfaces.Handler[TRequest, TResponse]) *singleflightHandler[TRequest, TResponse] {
+	return &singleflightHandler[TRequest, TResponse]{
+		handler: handler,
+		calls:   make(map[any]*singleflightCall[TResponse]),
+	}
+}
+
+// Handle joins the in-flight execution for an identical request, or starts a new one, then
+// waits for it to complete or for the context found in params to be done. The handler receives
+// the params of the caller that started the execution, with its context detached from
+// cancellation so that the starter giving up does not fail the callers that joined it.
+func (h *singleflightHandler[TRequest, TResponse]) Handle(request TRequest, params ...any) (TResponse, error) {
+	key, ok := singleflightKey(request)
+	if !ok {
+		return h.handler.Handle(request, params...)
+	}
+
+	h.mu.Lock()
+	call, found := h.calls[key]
+	if !found {
+		call = &singleflightCall[TResponse]{done: make(chan struct{})}
+		h.calls[key] = call
+		id := inFlight.add(AbandonedWork{Kind: "singleflight", RequestType: reflect.TypeFor[TRequest]().String()})
+		go h.execute(key, call, id, request, contexts.Detach(params))
+	}
+	h.mu.Unlock()
+
+	ctx := ContextFrom(params...)
+	select {
+	case <-call.done:
+		return call.response, call.err
+	case <-ctx.Done():
+		var response TResponse
+		return response, ctx.Err()
+	}
+}
+
+// execute runs the handler and wakes up every caller waiting on the call, then stops tracking
+// the execution. A panic is converted into an error because it would otherwise surface on the
+// goroutine running the execution.
+func (h *singleflightHandler[TRequest, TResponse]) execute(key any, call *singleflightCall[TResponse], id uint64, request TRequest, params []any) {
+	defer func() {
+		if r := recover(); r != nil {
+			call.err = fmt.Errorf("singleflight handler panicked: %v", r)
+		}
+		h.mu.Lock()
+		delete(h.calls, key)
+		h.mu.Unlock()
+		close(call.done)
+		inFlight.end(id)
+	}()
+
+	call.response, call.err = h.handler.Handle(request, params...)
+}
+
+// singleflightKey returns the key identifying identical requests.
+func singleflightKey(request any) (any, bool) {
+	if keyer, ok := request.(SingleflightKeyer); ok {
+		return keyer.SingleflightKey(), true
+	}
+	if request == nil || !reflect.ValueOf(request).Comparable() {
+		return nil, false
+	}
+	return request, true
+}
@@ -0,0 +1,64 @@
+package godiator
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+// namedSubscriber is implemented by the subscriber wrappers of the core registry.
+type namedSubscriber interface {
+	interfaces.Subscriber[any]
+	HandleFallible(event any, params ...any) error
+	Name() string
+}
+
+// handleSubscriber runs a subscriber for a published event through the notification pipelines
+// of the event type. An error returned by a notification pipeline on its own is logged, since
+// the failures of the subscriber are already reported by runSubscriber.
+func handleSubscriber[TRequest any](subscriber namedSubscriber, request TRequest, params ...any) {
+	var subscriberErr error
+	invoke := func(event any, params ...any) error {
+		subscriberErr = runSubscriber[TRequest](subscriber, event, params...)
+		return subscriberErr
+	}
+	for _, pipeline := range slices.Backward(core.GetNotificationPipelines[TRequest]()) {
+		next := invoke
+		invoke = func(event any, params ...any) error {
+			return pipeline.Handle(event, next, params...)
+		}
+	}
+	if err := invoke(request, params...); err != nil && !errors.Is(err, subscriberErr) {
+		slog.Error("notification pipeline failed", slog.String("event_type", reflect.TypeFor[TRequest]().String()),
+			slog.String("subscriber", subscriber.Name()), slog.String("error", err.Error()))
+	}
+}
+
+// runSubscriber runs a subscriber, reporting it to the metrics recorder, the tracer and the
+// observers, along with the error a fallible subscriber finally failed with. An event a
+// notification pipeline replaced by another type fails without reaching the subscriber.
+func runSubscriber[TRequest any](subscriber namedSubscriber, event any, params ...any) (err error) {
+	eventType := reflect.TypeFor[TRequest]().String()
+	name := subscriber.Name()
+	if metrics := core.GetMetrics(); metrics != nil {
+		metrics.SubscriberStarted(eventType, name)
+		defer observeSubscriber(metrics, eventType, name, time.Now(), &err)
+	}
+	if tracer := core.GetTracer(); tracer != nil {
+		var span interfaces.Span
+		span, params = startSubscriberSpan(tracer, eventType, name, params)
+		defer finishSpan(span, &err)
+	}
+	notifyObservers(func(o interfaces.Observer) { o.SubscriberStarted(event, name) })
+	defer notifySubscriberDone(event, name, time.Now(), &err)
+	if _, ok := event.(TRequest); !ok {
+		return fmt.Errorf(`notification pipeline replaced the "%s" event with a %T`, eventType, event)
+	}
+	return subscriber.HandleFallible(event, params...)
+}
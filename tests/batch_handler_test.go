@@ -0,0 +1,191 @@
+// Test Suite for Batch Handlers
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	BatchRequest struct {
+		ID int
+	}
+	BatchResponse struct {
+		ID int
+	}
+	BatchHandler struct {
+		mu          sync.Mutex
+		calls       [][]BatchRequest
+		contextErrs []error
+		principals  []string
+		err         error
+	}
+	BatchPrincipal struct {
+		Name string
+	}
+)
+
+func (p BatchPrincipal) HasPermission(permission string) bool { return true }
+
+func (h *BatchHandler) Handle(requests []BatchRequest, params ...any) ([]BatchResponse, error) {
+	h.mu.Lock()
+	h.calls = append(h.calls, requests)
+	h.contextErrs = append(h.contextErrs, godiator.ContextFrom(params...).Err())
+	if principal, ok := godiator.PrincipalFrom(params...); ok {
+		h.principals = append(h.principals, principal.(BatchPrincipal).Name)
+	}
+	h.mu.Unlock()
+
+	if h.err != nil {
+		return nil, h.err
+	}
+	responses := make([]BatchResponse, 0, len(requests))
+	for _, request := range requests {
+		responses = append(responses, BatchResponse{ID: request.ID * 10})
+	}
+	return responses, nil
+}
+
+type BatchHandlerTestSuite struct {
+	suite.Suite
+}
+
+// Run Batch Handler Test Suite
+func TestBatchHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(BatchHandlerTestSuite))
+}
+
+func (s *BatchHandlerTestSuite) TearDownTest() {
+	godiator.UnregisterHandler[BatchRequest]()
+}
+
+func (s *BatchHandlerTestSuite) sendConcurrently(count int) ([]BatchResponse, []error) {
+	var wg sync.WaitGroup
+	responses := make([]BatchResponse, count)
+	errs := make([]error, count)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i], errs[i] = godiator.Send[BatchRequest, BatchResponse](BatchRequest{ID: i})
+		}(i)
+	}
+	wg.Wait()
+	return responses, errs
+}
+
+// Test requests of different principals are not coalesced into the same batch
+func (s *BatchHandlerTestSuite) TestBatchHandler_GroupedByPrincipal() {
+	// Given
+	handler := &BatchHandler{}
+	godiator.RegisterBatchHandler[BatchRequest, BatchResponse](handler, godiator.WithBatchWindow(50*time.Millisecond))
+	alice := godiator.ContextWithPrincipal(context.Background(), BatchPrincipal{Name: "alice"})
+	bob := godiator.ContextWithPrincipal(context.Background(), BatchPrincipal{Name: "bob"})
+
+	// When
+	var wg sync.WaitGroup
+	for i, ctx := range []context.Context{alice, bob, alice, bob} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = godiator.Send[BatchRequest, BatchResponse](BatchRequest{ID: i}, ctx)
+		}()
+	}
+	wg.Wait()
+
+	// Then
+	s.Len(handler.calls, 2)
+	s.Len(handler.calls[0], 2)
+	s.Len(handler.calls[1], 2)
+	s.ElementsMatch([]string{"alice", "bob"}, handler.principals)
+	for i, call := range handler.calls {
+		owner := map[string]int{"alice": 0, "bob": 1}[handler.principals[i]]
+		for _, request := range call {
+			s.Equal(owner, request.ID%2)
+		}
+	}
+}
+
+// Test concurrent sends are coalesced into a single batch
+func (s *BatchHandlerTestSuite) TestBatchHandler_CoalescesConcurrentSends() {
+	// Given
+	handler := &BatchHandler{}
+	godiator.RegisterBatchHandler[BatchRequest, BatchResponse](handler, godiator.WithBatchWindow(50*time.Millisecond))
+
+	// When
+	responses, errs := s.sendConcurrently(10)
+
+	// Then
+	for i := range responses {
+		s.Nil(errs[i])
+		s.Equal(BatchResponse{ID: i * 10}, responses[i])
+	}
+	s.Len(handler.calls, 1)
+	s.Len(handler.calls[0], 10)
+}
+
+// Test a batch is dispatched once it reaches the maximum size
+func (s *BatchHandlerTestSuite) TestBatchHandler_MaxBatchSize() {
+	// Given
+	handler := &BatchHandler{}
+	godiator.RegisterBatchHandler[BatchRequest, BatchResponse](handler,
+		godiator.WithBatchWindow(time.Hour),
+		godiator.WithMaxBatchSize(5),
+	)
+
+	// When
+	responses, errs := s.sendConcurrently(10)
+
+	// Then
+	for i := range responses {
+		s.Nil(errs[i])
+		s.Equal(BatchResponse{ID: i * 10}, responses[i])
+	}
+	s.Len(handler.calls, 2)
+}
+
+// Test a batch error is returned to every caller
+func (s *BatchHandlerTestSuite) TestBatchHandler_Error() {
+	// Given
+	handler := &BatchHandler{err: errors.New("database unavailable")}
+	godiator.RegisterBatchHandler[BatchRequest, BatchResponse](handler)
+
+	// When
+	_, errs := s.sendConcurrently(3)
+
+	// Then
+	for _, err := range errs {
+		s.EqualError(err, "database unavailable")
+	}
+}
+
+// Test a cancelled caller stops waiting without failing the rest of the batch
+func (s *BatchHandlerTestSuite) TestBatchHandler_CancelledCaller() {
+	// Given
+	handler := &BatchHandler{}
+	godiator.RegisterBatchHandler[BatchRequest, BatchResponse](handler, godiator.WithBatchWindow(50*time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(5*time.Millisecond, cancel)
+
+	// When
+	started := time.Now()
+	_, cancelledErr := godiator.Send[BatchRequest, BatchResponse](BatchRequest{ID: 1}, ctx)
+	elapsed := time.Since(started)
+	response, err := godiator.Send[BatchRequest, BatchResponse](BatchRequest{ID: 2})
+
+	// Then
+	s.ErrorIs(cancelledErr, context.Canceled)
+	s.Less(elapsed, 50*time.Millisecond)
+	s.Nil(err)
+	s.Equal(BatchResponse{ID: 20}, response)
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	s.Equal([][]BatchRequest{{{ID: 1}, {ID: 2}}}, handler.calls)
+	s.Equal([]error{nil}, handler.contextErrs)
+}
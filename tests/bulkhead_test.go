@@ -0,0 +1,113 @@
+// Test Suite for Bulkhead Handlers
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	ReportRequest  struct{}
+	ReportResponse struct{}
+	ReportHandler  struct {
+		release chan struct{}
+	}
+)
+
+func (h *ReportHandler) Handle(request ReportRequest, params ...any) (ReportResponse, error) {
+	<-h.release
+	return ReportResponse{}, nil
+}
+
+type BulkheadTestSuite struct {
+	suite.Suite
+	handler *ReportHandler
+	wg      sync.WaitGroup
+}
+
+// Run Bulkhead Test Suite
+func TestBulkheadTestSuite(t *testing.T) {
+	suite.Run(t, new(BulkheadTestSuite))
+}
+
+func (s *BulkheadTestSuite) SetupTest() {
+	s.handler = &ReportHandler{release: make(chan struct{})}
+	godiator.RegisterHandler[ReportRequest, ReportResponse](s.handler, godiator.WithMaxConcurrency(2, 1))
+}
+
+func (s *BulkheadTestSuite) TearDownTest() {
+	godiator.UnregisterHandler[ReportRequest]()
+}
+
+func (s *BulkheadTestSuite) sendInBackground(count int) []error {
+	errs := make([]error, count)
+	for i := 0; i < count; i++ {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			_, errs[i] = godiator.Send[ReportRequest, ReportResponse](ReportRequest{})
+		}()
+	}
+	s.Eventually(func() bool {
+		stats, _ := godiator.HandlerStats[ReportRequest]()
+		return stats.InFlight+stats.Queued == count
+	}, time.Second, 5*time.Millisecond)
+	return errs
+}
+
+// Test requests beyond the concurrency cap and queue are rejected
+func (s *BulkheadTestSuite) TestBulkhead_RejectsWhenFull() {
+	// Given
+	errs := s.sendInBackground(3)
+
+	// When
+	_, err := godiator.Send[ReportRequest, ReportResponse](ReportRequest{})
+
+	// Then
+	s.ErrorIs(err, godiator.ErrBulkheadFull)
+	s.EqualError(err, `handler for "tests.ReportRequest" is at capacity (2 in flight, 1 queued)`)
+
+	stats, ok := godiator.HandlerStats[ReportRequest]()
+	s.True(ok)
+	s.Equal(godiator.BulkheadStats{InFlight: 2, Queued: 1, MaxConcurrency: 2, MaxQueue: 1}, stats)
+
+	close(s.handler.release)
+	s.wg.Wait()
+	for _, err := range errs {
+		s.Nil(err)
+	}
+}
+
+// Test queued requests stop waiting when their context is done
+func (s *BulkheadTestSuite) TestBulkhead_QueuedRequestCancelled() {
+	// Given
+	s.sendInBackground(2)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// When
+	_, err := godiator.Send[ReportRequest, ReportResponse](ReportRequest{}, ctx)
+
+	// Then
+	s.ErrorIs(err, context.DeadlineExceeded)
+	stats, _ := godiator.HandlerStats[ReportRequest]()
+	s.Equal(0, stats.Queued)
+
+	close(s.handler.release)
+	s.wg.Wait()
+}
+
+// Test stats are only available for handlers registered with a concurrency cap
+func (s *BulkheadTestSuite) TestBulkhead_StatsRemovedOnUnregister() {
+	// When
+	godiator.UnregisterHandler[ReportRequest]()
+
+	// Then
+	_, ok := godiator.HandlerStats[ReportRequest]()
+	s.False(ok)
+}
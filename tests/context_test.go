@@ -0,0 +1,76 @@
+// Test Suite for Context Propagation
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/mockiator"
+	"github.com/stretchr/testify/suite"
+)
+
+type contextKey struct{}
+
+type (
+	ContextRequest  struct{}
+	ContextResponse struct {
+		Value any
+	}
+)
+
+type ContextTestSuite struct {
+	suite.Suite
+}
+
+// Run Context Test Suite
+func TestContextTestSuite(t *testing.T) {
+	suite.Run(t, new(ContextTestSuite))
+}
+
+// Test a missing context falls back to the background context
+func (s *ContextTestSuite) TestContextFrom_Background() {
+	s.Equal(context.Background(), godiator.ContextFrom("param", 1))
+}
+
+// Test WithContext replaces the existing context
+func (s *ContextTestSuite) TestWithContext_Replaces() {
+	// Given
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+	params := []any{"param", context.Background()}
+
+	// When
+	result := godiator.WithContext(ctx, params...)
+
+	// Then
+	s.Equal([]any{"param", ctx}, result)
+	s.Equal(context.Background(), params[1])
+}
+
+// Test WithContext prepends a context when there is none
+func (s *ContextTestSuite) TestWithContext_Prepends() {
+	// Given
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+
+	// When
+	result := godiator.WithContext(ctx, "param")
+
+	// Then
+	s.Equal([]any{ctx, "param"}, result)
+}
+
+// Test the context passed to Send reaches the handler
+func (s *ContextTestSuite) TestSend_PropagatesContext() {
+	// Given
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+	mockiator.OnSend(func(request ContextRequest, params ...any) (ContextResponse, error) {
+		return ContextResponse{Value: godiator.ContextFrom(params...).Value(contextKey{})}, nil
+	})
+
+	// When
+	response, err := godiator.Send[ContextRequest, ContextResponse](ContextRequest{}, ctx)
+
+	// Then
+	s.Nil(err)
+	s.Equal("value", response.Value)
+}
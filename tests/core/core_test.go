@@ -83,3 +83,20 @@ func (s *PipelineCoreTestSuite) TestPipelineRegisteryActions() {
 
 	s.Empty(pipelines)
 }
+
+func (s *SubscriberCoreTestSuite) TestPublisherRegisteryActions() {
+	_, ok := core.GetPublisher[samples.MyRequest]()
+
+	s.False(ok)
+
+	var published []samples.MyRequest
+	core.SetPublisher(func(request samples.MyRequest, params ...any) error {
+		published = append(published, request)
+		return nil
+	})
+	publish, ok := core.GetPublisher[samples.MyRequest]()
+
+	s.True(ok)
+	s.Nil(publish(samples.MyRequest{}))
+	s.Len(published, 1)
+}
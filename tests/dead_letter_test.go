@@ -0,0 +1,291 @@
+// Test Suite for Subscriber Retries and Dead Letters
+package tests
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/core/interfaces"
+	"github.com/baranius/godiator/deadletter"
+	"github.com/baranius/godiator/pipeline"
+	"github.com/stretchr/testify/suite"
+)
+
+type InvoiceIssued struct {
+	InvoiceID string
+}
+
+// Fails until the configured number of calls is reached
+type FlakySubscriber struct {
+	failures int32
+	calls    atomic.Int32
+	handled  chan InvoiceIssued
+}
+
+func (s *FlakySubscriber) Handle(request InvoiceIssued, params ...any) error {
+	if s.calls.Add(1) <= s.failures {
+		return errors.New("downstream unavailable")
+	}
+	s.handled <- request
+	return nil
+}
+
+// Panics on every call
+type PanickingSubscriber struct {
+	calls atomic.Int32
+}
+
+func (s *PanickingSubscriber) Handle(request InvoiceIssued, params ...any) {
+	s.calls.Add(1)
+	panic("boom")
+}
+
+// Counts the subscriber invocations it wraps
+type CountingNotificationPipeline struct {
+	calls atomic.Int32
+}
+
+func (p *CountingNotificationPipeline) Handle(event any, next func(event any, params ...any) error, params ...any) error {
+	p.calls.Add(1)
+	return next(event, params...)
+}
+
+// Reports the subscriber failures it receives
+type SubscriberFailureObserver struct {
+	godiator.BaseObserver
+	failures chan string
+}
+
+func (o *SubscriberFailureObserver) SubscriberFailed(event any, subscriber string, err error) {
+	o.failures <- subscriber + ": " + err.Error()
+}
+
+type DeadLetterTestSuite struct {
+	suite.Suite
+	sink  *deadletter.MemorySink
+	retry godiator.RetryPolicy
+}
+
+// Run Dead Letter Test Suite
+func TestDeadLetterTestSuite(t *testing.T) {
+	suite.Run(t, new(DeadLetterTestSuite))
+}
+
+func (s *DeadLetterTestSuite) SetupTest() {
+	s.sink = deadletter.NewMemorySink()
+	s.retry = godiator.RetryPolicy{MaxAttempts: 3, Backoff: func(int) time.Duration { return time.Millisecond }}
+}
+
+func (s *DeadLetterTestSuite) TearDownTest() {
+	core.ClearNotificationPipelines()
+	godiator.UnregisterSubscriber[InvoiceIssued]()
+}
+
+func (s *DeadLetterTestSuite) waitForDeadLetters(sink interfaces.DeadLetterSink, count int) []interfaces.DeadLetter {
+	var letters []interfaces.DeadLetter
+	s.Eventually(func() bool {
+		letters, _ = sink.List()
+		return len(letters) == count
+	}, time.Second, time.Millisecond)
+	return letters
+}
+
+// Test failing subscribers are retried until they succeed
+func (s *DeadLetterTestSuite) TestRetry_Succeeds() {
+	// Given
+	subscriber := &FlakySubscriber{failures: 2, handled: make(chan InvoiceIssued, 1)}
+	godiator.RegisterFallibleSubscriber[InvoiceIssued](subscriber, godiator.WithRetry(s.retry), godiator.WithDeadLetterSink(s.sink))
+
+	// When
+	godiator.Publish(InvoiceIssued{InvoiceID: "inv-1"})
+
+	// Then
+	s.Equal(InvoiceIssued{InvoiceID: "inv-1"}, <-subscriber.handled)
+	s.Equal(int32(3), subscriber.calls.Load())
+	letters, _ := s.sink.List()
+	s.Empty(letters)
+}
+
+// Test events are dead-lettered once retries are exhausted, and can be re-driven
+func (s *DeadLetterTestSuite) TestRetry_DeadLetterAndRedrive() {
+	// Given
+	subscriber := &FlakySubscriber{failures: 3, handled: make(chan InvoiceIssued, 1)}
+	godiator.RegisterFallibleSubscriber[InvoiceIssued](subscriber, godiator.WithRetry(s.retry), godiator.WithDeadLetterSink(s.sink))
+
+	// When
+	godiator.Publish(InvoiceIssued{InvoiceID: "inv-1"})
+	letters := s.waitForDeadLetters(s.sink, 1)
+	err := godiator.Redrive(s.sink, letters[0].ID)
+
+	// Then
+	s.Equal("tests.InvoiceIssued", letters[0].EventType)
+	s.Equal("*tests.FlakySubscriber", letters[0].Subscriber)
+	s.Equal(InvoiceIssued{InvoiceID: "inv-1"}, letters[0].Event)
+	s.Equal("downstream unavailable", letters[0].Error)
+	s.Equal(3, letters[0].Attempts)
+	s.Nil(err)
+	s.Equal(InvoiceIssued{InvoiceID: "inv-1"}, <-subscriber.handled)
+	letters, _ = s.sink.List()
+	s.Empty(letters)
+}
+
+// Test retries are not cut short by cancelling the context passed to Publish
+func (s *DeadLetterTestSuite) TestRetry_CancelledPublishContext() {
+	// Given
+	subscriber := &FlakySubscriber{failures: 3, handled: make(chan InvoiceIssued, 1)}
+	godiator.RegisterFallibleSubscriber[InvoiceIssued](subscriber, godiator.WithRetry(s.retry), godiator.WithDeadLetterSink(s.sink))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// When
+	godiator.Publish(InvoiceIssued{InvoiceID: "inv-1"}, ctx)
+
+	// Then
+	letters := s.waitForDeadLetters(s.sink, 1)
+	s.Equal(3, letters[0].Attempts)
+	s.Equal(int32(3), subscriber.calls.Load())
+}
+
+// Test re-driven events go through the notification pipelines
+func (s *DeadLetterTestSuite) TestRedrive_NotificationPipelines() {
+	// Given
+	notificationPipeline := &CountingNotificationPipeline{}
+	godiator.RegisterNotificationPipelineFor[InvoiceIssued](notificationPipeline)
+	subscriber := &FlakySubscriber{failures: 1, handled: make(chan InvoiceIssued, 1)}
+	godiator.RegisterFallibleSubscriber[InvoiceIssued](subscriber, godiator.WithDeadLetterSink(s.sink))
+	godiator.Publish(InvoiceIssued{InvoiceID: "inv-3"})
+	letters := s.waitForDeadLetters(s.sink, 1)
+
+	// When
+	err := godiator.Redrive(s.sink, letters[0].ID)
+
+	// Then
+	s.Nil(err)
+	s.Equal(InvoiceIssued{InvoiceID: "inv-3"}, <-subscriber.handled)
+	s.Equal(int32(2), notificationPipeline.calls.Load())
+}
+
+// Test panicking subscribers are retried and dead-lettered
+func (s *DeadLetterTestSuite) TestRetry_Panic() {
+	// Given
+	subscriber := &PanickingSubscriber{}
+	godiator.RegisterSubscriber[InvoiceIssued](subscriber, godiator.WithRetry(s.retry), godiator.WithDeadLetterSink(s.sink), godiator.WithSubscriberName("billing"))
+
+	// When
+	godiator.Publish(InvoiceIssued{InvoiceID: "inv-1"})
+
+	// Then
+	letters := s.waitForDeadLetters(s.sink, 1)
+	s.Equal("billing", letters[0].Subscriber)
+	s.Equal("panic: boom", letters[0].Error)
+	s.Equal(int32(3), subscriber.calls.Load())
+}
+
+// Test subscribers that only set a name still panic, and the panic is reported as a failure
+func (s *DeadLetterTestSuite) TestSubscriberName_Panic() {
+	// Given
+	recovered := make(chan any, 1)
+	godiator.RegisterNotificationPipeline(pipeline.NewRecovery(func(event any, r any, stack []byte) {
+		recovered <- r
+	}))
+	observer := &SubscriberFailureObserver{failures: make(chan string, 1)}
+	godiator.RegisterObserver(observer)
+	defer godiator.UnregisterObserver(observer)
+	subscriber := &PanickingSubscriber{}
+	godiator.RegisterSubscriber[InvoiceIssued](subscriber, godiator.WithSubscriberName("billing"))
+
+	// When
+	godiator.Publish(InvoiceIssued{InvoiceID: "inv-1"})
+
+	// Then
+	s.Equal("boom", <-recovered)
+	s.Equal("billing: panic: boom", <-observer.failures)
+	s.Equal(int32(1), subscriber.calls.Load())
+}
+
+// Test the error a subscriber still fails with after every retry is reported as a failure
+func (s *DeadLetterTestSuite) TestRetry_ReportsFailure() {
+	// Given
+	observer := &SubscriberFailureObserver{failures: make(chan string, 1)}
+	godiator.RegisterObserver(observer)
+	defer godiator.UnregisterObserver(observer)
+	subscriber := &FlakySubscriber{failures: 3, handled: make(chan InvoiceIssued, 1)}
+	godiator.RegisterFallibleSubscriber[InvoiceIssued](subscriber, godiator.WithRetry(s.retry), godiator.WithDeadLetterSink(s.sink))
+
+	// When
+	godiator.Publish(InvoiceIssued{InvoiceID: "inv-1"})
+
+	// Then
+	s.Equal("*tests.FlakySubscriber: downstream unavailable", <-observer.failures)
+	s.waitForDeadLetters(s.sink, 1)
+}
+
+// Test subscribers of an event type dead-lettering under the same name are rejected
+func (s *DeadLetterTestSuite) TestDeadLetterSink_DuplicateName() {
+	// Given
+	godiator.RegisterFallibleSubscriber[InvoiceIssued](&FlakySubscriber{}, godiator.WithDeadLetterSink(s.sink))
+
+	// When
+	register := func() {
+		godiator.RegisterFallibleSubscriber[InvoiceIssued](&FlakySubscriber{}, godiator.WithDeadLetterSink(s.sink))
+	}
+	registerNamed := func() {
+		godiator.RegisterFallibleSubscriber[InvoiceIssued](&FlakySubscriber{}, godiator.WithDeadLetterSink(s.sink), godiator.WithSubscriberName("archive"))
+	}
+
+	// Then
+	s.PanicsWithValue(`godiator: subscriber "*tests.FlakySubscriber" of tests.InvoiceIssued already has a dead letter sink; name it with WithSubscriberName`, register)
+	s.NotPanics(registerNamed)
+}
+
+// Test dead letters persisted to a file are decoded when re-driven
+func (s *DeadLetterTestSuite) TestRedrive_FileSink() {
+	// Given
+	sink, err := deadletter.NewFileSink(filepath.Join(s.T().TempDir(), "dead-letters.jsonl"))
+	s.Require().NoError(err)
+	subscriber := &FlakySubscriber{failures: 1, handled: make(chan InvoiceIssued, 1)}
+	godiator.RegisterFallibleSubscriber[InvoiceIssued](subscriber, godiator.WithDeadLetterSink(sink))
+	godiator.Publish(InvoiceIssued{InvoiceID: "inv-2"})
+	letters := s.waitForDeadLetters(sink, 1)
+
+	// When
+	err = godiator.Redrive(sink, letters[0].ID)
+
+	// Then
+	s.Nil(err)
+	s.Equal(InvoiceIssued{InvoiceID: "inv-2"}, <-subscriber.handled)
+	letters, _ = sink.List()
+	s.Empty(letters)
+}
+
+// Test re-driving fails for unknown dead letters and unregistered subscribers
+func (s *DeadLetterTestSuite) TestRedrive_Errors() {
+	// Given
+	s.sink.Put(interfaces.DeadLetter{ID: "orphan", EventType: "tests.InvoiceIssued", Subscriber: "gone"})
+
+	// When
+	notFoundErr := godiator.Redrive(s.sink, "missing")
+	orphanErr := godiator.Redrive(s.sink, "orphan")
+
+	// Then
+	s.ErrorIs(notFoundErr, godiator.ErrDeadLetterNotFound)
+	s.EqualError(orphanErr, `subscriber "gone" is not registered for "tests.InvoiceIssued"`)
+}
+
+// Test the exponential backoff doubles up to its cap
+func (s *DeadLetterTestSuite) TestExponentialBackoff() {
+	backoff := godiator.ExponentialBackoff(100*time.Millisecond, time.Second)
+
+	var delays []time.Duration
+	for attempt := 1; attempt <= 5; attempt++ {
+		delays = append(delays, backoff(attempt))
+	}
+
+	s.Equal([]time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}, delays)
+}
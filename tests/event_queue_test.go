@@ -0,0 +1,207 @@
+// Test Suite for Event Queue
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/mockiator"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	QueuedEvent struct {
+		ID int
+	}
+	FanOutEvent struct {
+		Leaves int
+	}
+	LeafEvent struct {
+		ID int
+	}
+)
+
+// QueuedEvents records the events handled during a single test, so that subscribers still
+// running from a previous test do not touch it.
+type QueuedEvents struct {
+	mu       sync.Mutex
+	received []int
+}
+
+type EventQueueTestSuite struct {
+	suite.Suite
+	release chan struct{}
+	started chan int
+	events  *QueuedEvents
+}
+
+// Run Event Queue Test Suite
+func TestEventQueueTestSuite(t *testing.T) {
+	suite.Run(t, new(EventQueueTestSuite))
+}
+
+func (s *EventQueueTestSuite) SetupTest() {
+	release, started, events := make(chan struct{}), make(chan int, 10), &QueuedEvents{}
+	s.release, s.started, s.events = release, started, events
+	// The subscriber blocks until released, keeping its worker busy
+	mockiator.OnPublish(func(request QueuedEvent, params ...any) {
+		started <- request.ID
+		<-release
+		events.mu.Lock()
+		events.received = append(events.received, request.ID)
+		events.mu.Unlock()
+	})
+}
+
+func (s *EventQueueTestSuite) TearDownTest() {
+	close(s.release)
+	godiator.DisableEventQueue()
+	core.RemoveSubscriber[QueuedEvent]()
+}
+
+// fill occupies the single worker with the first event and the single queue slot with the second
+func (s *EventQueueTestSuite) fill(overflow godiator.OverflowPolicy) {
+	godiator.UseEventQueue(godiator.EventQueueConfig{Capacity: 1, Workers: 1, Overflow: overflow})
+	s.Nil(godiator.Publish(QueuedEvent{ID: 1}))
+	s.Equal(1, <-s.started)
+	s.Nil(godiator.Publish(QueuedEvent{ID: 2}))
+}
+
+func (s *EventQueueTestSuite) receivedIDs() []int {
+	s.events.mu.Lock()
+	defer s.events.mu.Unlock()
+	return append([]int(nil), s.events.received...)
+}
+
+// Test the block policy waits for room until the context is done
+func (s *EventQueueTestSuite) TestEventQueue_Block() {
+	// Given
+	s.fill(godiator.OverflowBlock)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// When
+	err := godiator.Publish(QueuedEvent{ID: 3}, ctx)
+
+	// Then
+	s.ErrorIs(err, context.DeadlineExceeded)
+	stats, ok := godiator.QueueStats()
+	s.True(ok)
+	s.Equal(godiator.EventQueueStats{Depth: 1, Capacity: 1, Workers: 1, Enqueued: 2, Dropped: 1}, stats)
+}
+
+// Test replacing the queue wakes the publishers waiting for room, whose events still run
+func (s *EventQueueTestSuite) TestEventQueue_BlockedPublishReplaced() {
+	// Given
+	s.fill(godiator.OverflowBlock)
+	published := make(chan error, 1)
+	go func() { published <- godiator.Publish(QueuedEvent{ID: 3}) }()
+	// Let the publisher wait for room
+	time.Sleep(20 * time.Millisecond)
+
+	// When
+	disabled := make(chan struct{})
+	go func() {
+		godiator.DisableEventQueue()
+		close(disabled)
+	}()
+
+	// Then
+	select {
+	case <-disabled:
+	case <-time.After(time.Second):
+		s.FailNow("disabling the event queue waited for the blocked publisher")
+	}
+	s.Nil(<-published)
+	s.Equal(3, <-s.started)
+}
+
+// Test subscribers publishing from a worker into a full queue run the overflow on their worker
+func (s *EventQueueTestSuite) TestEventQueue_NestedPublishFromWorker() {
+	// Given
+	godiator.UseEventQueue(godiator.EventQueueConfig{Capacity: 1, Workers: 1, Overflow: godiator.OverflowBlock})
+	leaves, nested := make(chan int, 10), make(chan error, 10)
+	mockiator.OnPublish(func(request LeafEvent, params ...any) {
+		leaves <- request.ID
+	})
+	mockiator.OnPublish(func(request FanOutEvent, params ...any) {
+		for id := range request.Leaves {
+			nested <- godiator.Publish(LeafEvent{ID: id}, params...)
+		}
+	})
+	defer core.RemoveSubscriber[LeafEvent]()
+	defer core.RemoveSubscriber[FanOutEvent]()
+
+	// When
+	err := godiator.Publish(FanOutEvent{Leaves: 3})
+
+	// Then
+	s.Nil(err)
+	var received []int
+	for range 3 {
+		select {
+		case id := <-leaves:
+			received = append(received, id)
+		case <-time.After(time.Second):
+			s.FailNow("the worker is waiting for room in its own queue")
+		}
+		s.Nil(<-nested)
+	}
+	s.ElementsMatch([]int{0, 1, 2}, received)
+}
+
+// Test the drop newest policy discards the published event
+func (s *EventQueueTestSuite) TestEventQueue_DropNewest() {
+	// Given
+	s.fill(godiator.OverflowDropNewest)
+
+	// When
+	err := godiator.Publish(QueuedEvent{ID: 3})
+	s.release <- struct{}{}
+	s.release <- struct{}{}
+
+	// Then
+	s.Nil(err)
+	s.Eventually(func() bool { return len(s.receivedIDs()) == 2 }, time.Second, time.Millisecond)
+	s.Equal([]int{1, 2}, s.receivedIDs())
+}
+
+// Test the drop oldest policy discards queued events to make room
+func (s *EventQueueTestSuite) TestEventQueue_DropOldest() {
+	// Given
+	s.fill(godiator.OverflowDropOldest)
+
+	// When
+	err := godiator.Publish(QueuedEvent{ID: 3})
+	s.release <- struct{}{}
+	s.release <- struct{}{}
+
+	// Then
+	s.Nil(err)
+	s.Eventually(func() bool { return len(s.receivedIDs()) == 2 }, time.Second, time.Millisecond)
+	s.Equal([]int{1, 3}, s.receivedIDs())
+	stats, _ := godiator.QueueStats()
+	s.Equal(uint64(1), stats.Dropped)
+}
+
+// Test the return error policy rejects the published event
+func (s *EventQueueTestSuite) TestEventQueue_ReturnError() {
+	// Given
+	s.fill(godiator.OverflowReturnError)
+
+	// When
+	err := godiator.Publish(QueuedEvent{ID: 3})
+
+	// Then
+	s.ErrorIs(err, godiator.ErrEventQueueFull)
+}
+
+// Test the event queue is reported as disabled by default
+func (s *EventQueueTestSuite) TestEventQueue_Disabled() {
+	_, ok := godiator.QueueStats()
+	s.False(ok)
+}
@@ -0,0 +1,193 @@
+// Test Suite for Metrics
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/metrics"
+	"github.com/baranius/godiator/mockiator"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	OrderRequest  struct{ Fail bool }
+	OrderResponse struct{}
+	OrderPlaced   struct{}
+)
+
+// Fails on every call
+type RejectingSubscriber struct{}
+
+func (s *RejectingSubscriber) Handle(request OrderPlaced, params ...any) error {
+	return errors.New("projection offline")
+}
+
+// expvarNames keeps expvar names unique across repeated runs of the suite
+var expvarNames atomic.Int32
+
+type MetricsTestSuite struct {
+	suite.Suite
+	registry *metrics.Registry
+}
+
+// Run Metrics Test Suite
+func TestMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsTestSuite))
+}
+
+func (s *MetricsTestSuite) SetupTest() {
+	s.registry = metrics.NewRegistry(metrics.WithBuckets(0.1, 1))
+	godiator.SetMetrics(s.registry)
+	mockiator.OnSend(func(request OrderRequest, params ...any) (OrderResponse, error) {
+		if request.Fail {
+			return OrderResponse{}, errors.New("out of stock")
+		}
+		return OrderResponse{}, nil
+	})
+}
+
+func (s *MetricsTestSuite) TearDownTest() {
+	godiator.SetMetrics(nil)
+	core.RemoveSubscriber[OrderPlaced]()
+}
+
+// Test Send records calls, errors and latency per request type
+func (s *MetricsTestSuite) TestMetrics_Send() {
+	// When
+	godiator.Send[OrderRequest, OrderResponse](OrderRequest{})
+	godiator.Send[OrderRequest, OrderResponse](OrderRequest{Fail: true})
+
+	// Then
+	snapshot := s.registry.Snapshot()
+	s.Len(snapshot.Requests, 1)
+	stats := snapshot.Requests[0]
+	s.Equal("tests.OrderRequest", stats.RequestType)
+	s.Equal(uint64(2), stats.Calls)
+	s.Equal(uint64(1), stats.Errors)
+	s.Equal(int64(0), stats.InFlight)
+	s.Equal(uint64(2), stats.Latency.Count)
+	s.Equal([]metrics.Bucket{{UpperBound: 0.1, Count: 2}, {UpperBound: 1, Count: 2}}, stats.Latency.Buckets)
+}
+
+// Test a panicking handler is recorded as an error and the panic propagated
+func (s *MetricsTestSuite) TestMetrics_SendPanic() {
+	// Given
+	mockiator.OnSend(func(request OrderRequest, params ...any) (OrderResponse, error) {
+		panic("boom")
+	})
+
+	// When
+	s.PanicsWithValue("boom", func() {
+		godiator.Send[OrderRequest, OrderResponse](OrderRequest{})
+	})
+
+	// Then
+	stats := s.registry.Snapshot().Requests[0]
+	s.Equal(uint64(1), stats.Errors)
+	s.Equal(int64(0), stats.InFlight)
+}
+
+// Test Publish records every subscriber separately
+func (s *MetricsTestSuite) TestMetrics_Publish() {
+	// Given
+	var wg sync.WaitGroup
+	wg.Add(2)
+	mockiator.OnPublish(func(request OrderPlaced, params ...any) { wg.Done() })
+	mockiator.OnPublish(func(request OrderPlaced, params ...any) { wg.Done() })
+
+	// When
+	godiator.Publish(OrderPlaced{})
+	wg.Wait()
+
+	// Then
+	s.Eventually(func() bool {
+		subscribers := s.registry.Snapshot().Subscribers
+		return len(subscribers) == 1 && subscribers[0].Latency.Count == 2
+	}, time.Second, time.Millisecond)
+	stats := s.registry.Snapshot().Subscribers[0]
+	s.Equal("tests.OrderPlaced", stats.EventType)
+	s.Contains(stats.Subscriber, "mockSubscriber")
+	s.Equal(uint64(2), stats.Calls)
+	s.Equal(uint64(0), stats.Errors)
+}
+
+// Test the errors of fallible subscribers are recorded
+func (s *MetricsTestSuite) TestMetrics_FallibleSubscriber() {
+	// Given
+	godiator.RegisterFallibleSubscriber[OrderPlaced](&RejectingSubscriber{})
+
+	// When
+	godiator.Publish(OrderPlaced{})
+
+	// Then
+	s.Eventually(func() bool {
+		subscribers := s.registry.Snapshot().Subscribers
+		return len(subscribers) == 1 && subscribers[0].Latency.Count == 1
+	}, time.Second, time.Millisecond)
+	stats := s.registry.Snapshot().Subscribers[0]
+	s.Equal("*tests.RejectingSubscriber", stats.Subscriber)
+	s.Equal(uint64(1), stats.Calls)
+	s.Equal(uint64(1), stats.Errors)
+}
+
+// Test metrics are rendered in the Prometheus text format
+func (s *MetricsTestSuite) TestMetrics_WritePrometheus() {
+	// Given
+	godiator.Send[OrderRequest, OrderResponse](OrderRequest{Fail: true})
+
+	// When
+	var buf bytes.Buffer
+	err := s.registry.WritePrometheus(&buf)
+
+	// Then
+	s.Nil(err)
+	output := buf.String()
+	s.Contains(output, "# TYPE godiator_requests_total counter\n")
+	s.Contains(output, `godiator_requests_total{request_type="tests.OrderRequest"} 1`)
+	s.Contains(output, `godiator_requests_errors_total{request_type="tests.OrderRequest"} 1`)
+	s.Contains(output, `godiator_requests_in_flight{request_type="tests.OrderRequest"} 0`)
+	s.Contains(output, `godiator_requests_duration_seconds_bucket{request_type="tests.OrderRequest",le="0.1"} 1`)
+	s.Contains(output, `godiator_requests_duration_seconds_bucket{request_type="tests.OrderRequest",le="+Inf"} 1`)
+	s.Contains(output, `godiator_requests_duration_seconds_count{request_type="tests.OrderRequest"} 1`)
+}
+
+// Test metrics are published to expvar
+func (s *MetricsTestSuite) TestMetrics_PublishExpvar() {
+	// Given
+	godiator.Send[OrderRequest, OrderResponse](OrderRequest{})
+
+	name := fmt.Sprintf("godiator_test_%d", expvarNames.Add(1))
+
+	// When
+	s.registry.PublishExpvar(name)
+
+	// Then
+	s.True(strings.Contains(expvar.Get(name).String(), `"RequestType":"tests.OrderRequest"`))
+}
+
+// Test metrics are served as a scrape endpoint
+func (s *MetricsTestSuite) TestMetrics_ServeHTTP() {
+	// Given
+	godiator.Send[OrderRequest, OrderResponse](OrderRequest{})
+	recorder := httptest.NewRecorder()
+
+	// When
+	s.registry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// Then
+	s.Equal(http.StatusOK, recorder.Code)
+	s.Equal(metrics.ContentType, recorder.Header().Get("Content-Type"))
+	s.Contains(recorder.Body.String(), `godiator_requests_total{request_type="tests.OrderRequest"} 1`)
+}
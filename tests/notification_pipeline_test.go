@@ -0,0 +1,200 @@
+// Test Suite for Notification Pipelines
+package tests
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/mockiator"
+	"github.com/baranius/godiator/pipeline"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	ShipmentSent struct {
+		Tenant string
+	}
+	ShipmentLost    struct{}
+	ShipmentDelayed struct{}
+)
+
+// Records its label and tags the event with a tenant before calling the rest of the chain
+type TenantPipeline struct {
+	label string
+	mu    *sync.Mutex
+	trace *[]string
+}
+
+func (p *TenantPipeline) Handle(event any, next func(event any, params ...any) error, params ...any) error {
+	p.mu.Lock()
+	*p.trace = append(*p.trace, p.label)
+	p.mu.Unlock()
+	if shipment, ok := event.(ShipmentSent); ok && shipment.Tenant == "" {
+		event = ShipmentSent{Tenant: "acme"}
+	}
+	return next(event, params...)
+}
+
+// Reports the errors returned by the rest of the chain, optionally replacing the event first
+type ErrorCapturingPipeline struct {
+	replacement any
+	errs        chan error
+}
+
+func (p *ErrorCapturingPipeline) Handle(event any, next func(event any, params ...any) error, params ...any) error {
+	if p.replacement != nil {
+		event = p.replacement
+	}
+	err := next(event, params...)
+	p.errs <- err
+	return err
+}
+
+// Fails on every call
+type DelayedShipmentSubscriber struct {
+	calls atomic.Int32
+}
+
+func (s *DelayedShipmentSubscriber) Handle(request ShipmentDelayed, params ...any) error {
+	s.calls.Add(1)
+	return errors.New("carrier unreachable")
+}
+
+type NotificationPipelineTestSuite struct {
+	suite.Suite
+	mu    sync.Mutex
+	trace []string
+}
+
+// Run Notification Pipeline Test Suite
+func TestNotificationPipelineTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationPipelineTestSuite))
+}
+
+func (s *NotificationPipelineTestSuite) SetupTest() {
+	s.trace = nil
+	core.ClearNotificationPipelines()
+}
+
+func (s *NotificationPipelineTestSuite) TearDownTest() {
+	core.ClearNotificationPipelines()
+	core.RemoveSubscriber[ShipmentSent]()
+	core.RemoveSubscriber[ShipmentLost]()
+	godiator.UnregisterSubscriber[ShipmentDelayed]()
+}
+
+func (s *NotificationPipelineTestSuite) pipeline(label string) *TenantPipeline {
+	return &TenantPipeline{label: label, mu: &s.mu, trace: &s.trace}
+}
+
+// Test global pipelines run before the pipelines of the event type and may replace the event
+func (s *NotificationPipelineTestSuite) TestNotificationPipeline_Order() {
+	// Given
+	godiator.RegisterNotificationPipelineFor[ShipmentSent](s.pipeline("typed"))
+	godiator.RegisterNotificationPipeline(s.pipeline("global"))
+	received := make(chan ShipmentSent, 1)
+	mockiator.OnPublish(func(request ShipmentSent, params ...any) { received <- request })
+
+	// When
+	godiator.Publish(ShipmentSent{})
+
+	// Then
+	s.Equal(ShipmentSent{Tenant: "acme"}, <-received)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Equal([]string{"global", "typed"}, s.trace)
+}
+
+// Test pipelines of an event type do not wrap subscribers of other event types
+func (s *NotificationPipelineTestSuite) TestNotificationPipeline_OtherEventType() {
+	// Given
+	godiator.RegisterNotificationPipelineFor[ShipmentSent](s.pipeline("typed"))
+	received := make(chan ShipmentLost, 1)
+	mockiator.OnPublish(func(request ShipmentLost, params ...any) { received <- request })
+
+	// When
+	godiator.Publish(ShipmentLost{})
+
+	// Then
+	<-received
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Empty(s.trace)
+}
+
+// Test unregistering removes the pipelines of the event type only
+func (s *NotificationPipelineTestSuite) TestNotificationPipeline_Unregister() {
+	// Given
+	godiator.RegisterNotificationPipeline(s.pipeline("global"))
+	godiator.RegisterNotificationPipelineFor[ShipmentSent](s.pipeline("typed"))
+	received := make(chan ShipmentSent, 1)
+	mockiator.OnPublish(func(request ShipmentSent, params ...any) { received <- request })
+
+	// When
+	godiator.UnregisterNotificationPipelines[ShipmentSent]()
+	godiator.Publish(ShipmentSent{Tenant: "globex"})
+
+	// Then
+	s.Equal(ShipmentSent{Tenant: "globex"}, <-received)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Equal([]string{"global"}, s.trace)
+}
+
+// Test pipelines receive the error the subscriber failed with
+func (s *NotificationPipelineTestSuite) TestNotificationPipeline_SubscriberError() {
+	// Given
+	capturing := &ErrorCapturingPipeline{errs: make(chan error, 1)}
+	godiator.RegisterNotificationPipelineFor[ShipmentDelayed](capturing)
+	subscriber := &DelayedShipmentSubscriber{}
+	godiator.RegisterFallibleSubscriber[ShipmentDelayed](subscriber)
+
+	// When
+	godiator.Publish(ShipmentDelayed{})
+
+	// Then
+	s.EqualError(<-capturing.errs, "carrier unreachable")
+	s.Equal(int32(1), subscriber.calls.Load())
+}
+
+// Test an event replaced by one of another type fails without reaching the subscriber
+func (s *NotificationPipelineTestSuite) TestNotificationPipeline_ReplacedEventType() {
+	// Given
+	capturing := &ErrorCapturingPipeline{replacement: ShipmentLost{}, errs: make(chan error, 1)}
+	godiator.RegisterNotificationPipelineFor[ShipmentDelayed](capturing)
+	subscriber := &DelayedShipmentSubscriber{}
+	godiator.RegisterFallibleSubscriber[ShipmentDelayed](subscriber)
+
+	// When
+	godiator.Publish(ShipmentDelayed{})
+
+	// Then
+	s.EqualError(<-capturing.errs, `notification pipeline replaced the "tests.ShipmentDelayed" event with a tests.ShipmentLost`)
+	s.Equal(int32(0), subscriber.calls.Load())
+}
+
+// Test the recovery pipeline recovers panicking subscribers
+func (s *NotificationPipelineTestSuite) TestNotificationPipeline_Recovery() {
+	// Given
+	recovered := make(chan any, 1)
+	godiator.RegisterNotificationPipeline(pipeline.NewRecovery(func(event any, r any, stack []byte) {
+		recovered <- r
+	}))
+	mockiator.OnPublish(func(request ShipmentLost, params ...any) { panic("lost") })
+
+	// When
+	godiator.Publish(ShipmentLost{})
+
+	// Then
+	select {
+	case r := <-recovered:
+		s.Equal("lost", r)
+	case <-time.After(time.Second):
+		s.Fail("panic was not recovered")
+	}
+}
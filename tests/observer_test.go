@@ -0,0 +1,190 @@
+// Test Suite for Observers
+package tests
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/mockiator"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	ObservedRequest struct {
+		Fail bool
+	}
+	ObservedResponse struct {
+		Value string
+	}
+	ObservedEvent struct{}
+)
+
+// Records every notification it receives
+type RecordingObserver struct {
+	godiator.BaseObserver
+	mu     sync.Mutex
+	events []string
+	errs   []error
+}
+
+func (o *RecordingObserver) record(event string, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event)
+	if err != nil {
+		o.errs = append(o.errs, err)
+	}
+}
+
+func (o *RecordingObserver) recorded() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]string(nil), o.events...)
+}
+
+func (o *RecordingObserver) BeforeSend(request any, params ...any) {
+	o.record("before send", nil)
+}
+
+func (o *RecordingObserver) AfterSend(request any, response any, err error, duration time.Duration) {
+	o.record("after send", err)
+}
+
+func (o *RecordingObserver) BeforePublish(event any, params ...any) {
+	o.record("before publish", nil)
+}
+
+func (o *RecordingObserver) SubscriberStarted(event any, subscriber string) {
+	o.record("subscriber started", nil)
+}
+
+func (o *RecordingObserver) SubscriberFinished(event any, subscriber string, duration time.Duration) {
+	o.record("subscriber finished", nil)
+}
+
+func (o *RecordingObserver) HandlerRegistered(requestType string) {
+	o.record("registered "+requestType, nil)
+}
+
+func (o *RecordingObserver) HandlerUnregistered(requestType string) {
+	o.record("unregistered "+requestType, nil)
+}
+
+// Panics on every notification
+type PanickingObserver struct {
+	godiator.BaseObserver
+}
+
+func (o *PanickingObserver) AfterSend(request any, response any, err error, duration time.Duration) {
+	panic("observer failed")
+}
+
+type ObserverTestSuite struct {
+	suite.Suite
+	observer *RecordingObserver
+}
+
+// Run Observer Test Suite
+func TestObserverTestSuite(t *testing.T) {
+	suite.Run(t, new(ObserverTestSuite))
+}
+
+func (s *ObserverTestSuite) SetupTest() {
+	mockiator.OnSend(func(request ObservedRequest, params ...any) (ObservedResponse, error) {
+		if request.Fail {
+			return ObservedResponse{}, errors.New("failed")
+		}
+		return ObservedResponse{Value: "value"}, nil
+	})
+	s.observer = &RecordingObserver{}
+	godiator.RegisterObserver(s.observer)
+}
+
+func (s *ObserverTestSuite) TearDownTest() {
+	godiator.UnregisterObserver(s.observer)
+	core.RemoveSubscriber[ObservedEvent]()
+}
+
+// Test Send notifies observers before and after the request
+func (s *ObserverTestSuite) TestObserver_Send() {
+	// When
+	godiator.Send[ObservedRequest, ObservedResponse](ObservedRequest{})
+	godiator.Send[ObservedRequest, ObservedResponse](ObservedRequest{Fail: true})
+
+	// Then
+	s.Equal([]string{"before send", "after send", "before send", "after send"}, s.observer.recorded())
+	s.Len(s.observer.errs, 1)
+	s.EqualError(s.observer.errs[0], "failed")
+}
+
+// Test Publish notifies observers of the event and every subscriber
+func (s *ObserverTestSuite) TestObserver_Publish() {
+	// Given
+	mockiator.OnPublish(func(request ObservedEvent, params ...any) {})
+
+	// When
+	godiator.Publish(ObservedEvent{})
+
+	// Then
+	s.Eventually(func() bool {
+		return len(s.observer.recorded()) == 3
+	}, time.Second, time.Millisecond)
+	s.Equal([]string{"before publish", "subscriber started", "subscriber finished"}, s.observer.recorded())
+}
+
+// Test observers are notified of handler registrations
+func (s *ObserverTestSuite) TestObserver_HandlerRegistration() {
+	// When
+	godiator.UnregisterHandler[ObservedRequest]()
+	mockiator.OnSend(func(request ObservedRequest, params ...any) (ObservedResponse, error) {
+		return ObservedResponse{}, nil
+	})
+
+	// Then
+	s.Equal([]string{"unregistered tests.ObservedRequest", "registered tests.ObservedRequest"}, s.observer.recorded())
+}
+
+// Test panicking observers do not alter the response
+func (s *ObserverTestSuite) TestObserver_PanicIgnored() {
+	// Given
+	panicking := &PanickingObserver{}
+	godiator.RegisterObserver(panicking)
+	defer godiator.UnregisterObserver(panicking)
+
+	// When
+	response, err := godiator.Send[ObservedRequest, ObservedResponse](ObservedRequest{})
+
+	// Then
+	s.Nil(err)
+	s.Equal("value", response.Value)
+	s.Equal([]string{"before send", "after send"}, s.observer.recorded())
+}
+
+// Observer whose value is not comparable
+type TaggedObserver struct {
+	godiator.BaseObserver
+	tags []string
+}
+
+// Test observers whose type is not comparable can be unregistered without panicking
+func (s *ObserverTestSuite) TestObserver_NotComparable() {
+	// Given
+	observer := TaggedObserver{tags: []string{"audit"}}
+	registered := len(core.GetObservers())
+	unregister := godiator.RegisterObserver(observer)
+
+	// When
+	removedByValue := godiator.UnregisterObserver(observer)
+	removed := unregister()
+	removedTwice := unregister()
+
+	// Then
+	s.False(removedByValue)
+	s.True(removed)
+	s.False(removedTwice)
+	s.Len(core.GetObservers(), registered)
+}
@@ -0,0 +1,223 @@
+// Test Suite for Partitioned Events
+package tests
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/mockiator"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	OrderCreated struct {
+		OrderID string
+		Seq     int
+	}
+	OrderShipped struct {
+		OrderID string
+	}
+)
+
+func (e OrderCreated) PartitionKey() string { return e.OrderID }
+func (e OrderShipped) PartitionKey() string { return e.OrderID }
+
+// Projection of created orders, taking longer for earlier events
+type CreatedProjection struct {
+	record func(event string)
+}
+
+func (p *CreatedProjection) Handle(request OrderCreated, params ...any) {
+	time.Sleep(time.Duration(5-request.Seq) * time.Millisecond)
+	p.record("created " + request.OrderID)
+}
+
+// Projection of shipped orders
+type ShippedProjection struct {
+	record func(event string)
+}
+
+func (p *ShippedProjection) Handle(request OrderShipped, params ...any) {
+	p.record("shipped " + request.OrderID)
+}
+
+type PartitionTestSuite struct {
+	suite.Suite
+	mu       sync.Mutex
+	received []string
+}
+
+// Run Partition Test Suite
+func TestPartitionTestSuite(t *testing.T) {
+	suite.Run(t, new(PartitionTestSuite))
+}
+
+func (s *PartitionTestSuite) SetupTest() {
+	s.received = nil
+}
+
+func (s *PartitionTestSuite) TearDownTest() {
+	godiator.DisableEventQueue()
+	core.RemoveSubscriber[OrderCreated]()
+	core.RemoveSubscriber[OrderShipped]()
+}
+
+func (s *PartitionTestSuite) record(event string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.received = append(s.received, event)
+}
+
+func (s *PartitionTestSuite) recorded() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.received...)
+}
+
+// Test events with the same key are delivered in publish order to subscribers sharing a name,
+// across event types
+func (s *PartitionTestSuite) TestPartition_OrderedPerKey() {
+	// Given
+	godiator.RegisterSubscriber[OrderCreated](&CreatedProjection{record: s.record}, godiator.WithSubscriberName("orders"))
+	godiator.RegisterSubscriber[OrderShipped](&ShippedProjection{record: s.record}, godiator.WithSubscriberName("orders"))
+
+	// When
+	for seq := range 3 {
+		godiator.Publish(OrderCreated{OrderID: "order-1", Seq: seq})
+	}
+	godiator.Publish(OrderShipped{OrderID: "order-1"})
+
+	// Then
+	s.Eventually(func() bool { return len(s.recorded()) == 4 }, time.Second, time.Millisecond)
+	s.Equal([]string{"created order-1", "created order-1", "created order-1", "shipped order-1"}, s.recorded())
+}
+
+// Test events with different keys are delivered in parallel
+func (s *PartitionTestSuite) TestPartition_ParallelKeys() {
+	// Given
+	shipped := make(chan struct{})
+	mockiator.OnPublish(func(request OrderCreated, params ...any) {
+		// Only completes once the other key has been delivered
+		<-shipped
+		s.record("created " + request.OrderID)
+	})
+	mockiator.OnPublish(func(request OrderShipped, params ...any) {
+		s.record("shipped " + request.OrderID)
+		close(shipped)
+	})
+
+	// When
+	godiator.Publish(OrderCreated{OrderID: "order-1"})
+	godiator.Publish(OrderShipped{OrderID: "order-2"})
+
+	// Then
+	s.Eventually(func() bool { return len(s.recorded()) == 2 }, time.Second, time.Millisecond)
+	s.Equal([]string{"shipped order-2", "created order-1"}, s.recorded())
+}
+
+// Test a slow subscriber does not hold back the events of the same key for other subscribers
+func (s *PartitionTestSuite) TestPartition_OrderedPerSubscriber() {
+	// Given
+	release := make(chan struct{})
+	defer close(release)
+	mockiator.OnPublish(func(request OrderCreated, params ...any) {
+		<-release
+	})
+	godiator.RegisterSubscriber[OrderCreated](&CreatedProjection{record: s.record})
+
+	// When
+	godiator.Publish(OrderCreated{OrderID: "order-1", Seq: 4})
+	godiator.Publish(OrderCreated{OrderID: "order-1", Seq: 4})
+
+	// Then
+	s.Eventually(func() bool { return len(s.recorded()) == 2 }, time.Second, time.Millisecond)
+}
+
+// Test the event queue delivers the events of a key in order and bounds those waiting
+func (s *PartitionTestSuite) TestPartition_EventQueue() {
+	// Given
+	godiator.UseEventQueue(godiator.EventQueueConfig{Capacity: 2, Workers: 2, Overflow: godiator.OverflowReturnError})
+	release := make(chan struct{})
+	started := make(chan struct{}, 3)
+	mockiator.OnPublish(func(request OrderCreated, params ...any) {
+		started <- struct{}{}
+		<-release
+		s.record(fmt.Sprintf("created %s %d", request.OrderID, request.Seq))
+	})
+
+	// When
+	s.Nil(godiator.Publish(OrderCreated{OrderID: "order-1", Seq: 1}))
+	<-started
+	s.Nil(godiator.Publish(OrderCreated{OrderID: "order-1", Seq: 2}))
+	fullErr := godiator.Publish(OrderCreated{OrderID: "order-1", Seq: 3})
+	stats, _ := godiator.QueueStats()
+	close(release)
+
+	// Then
+	s.ErrorIs(fullErr, godiator.ErrEventQueueFull)
+	s.Equal(1, stats.Partitioned)
+	s.Equal(0, stats.Depth)
+	s.Eventually(func() bool { return len(s.recorded()) == 2 }, time.Second, time.Millisecond)
+	s.Equal([]string{"created order-1 1", "created order-1 2"}, s.recorded())
+}
+
+// Test the sequences of different event types sharing a key are not ordered together
+func (s *PartitionTestSuite) TestPartition_SequencesPerEventType() {
+	// Given
+	release := make(chan struct{})
+	defer close(release)
+	mockiator.OnPublish(func(request OrderCreated, params ...any) {
+		<-release
+	})
+	mockiator.OnPublish(func(request OrderShipped, params ...any) {
+		s.record("shipped " + request.OrderID)
+	})
+	godiator.SetDispatchMode[OrderCreated](godiator.DispatchSequential)
+	godiator.SetDispatchMode[OrderShipped](godiator.DispatchSequential)
+	defer godiator.SetDispatchMode[OrderCreated](godiator.DispatchConcurrent)
+	defer godiator.SetDispatchMode[OrderShipped](godiator.DispatchConcurrent)
+
+	// When
+	godiator.Publish(OrderCreated{OrderID: "order-1"})
+	godiator.Publish(OrderShipped{OrderID: "order-1"})
+
+	// Then
+	s.Eventually(func() bool { return len(s.recorded()) == 1 }, time.Second, time.Millisecond)
+}
+
+// Test Publish waits for room on a full lane until the context is done without the event queue
+func (s *PartitionTestSuite) TestPartition_BoundedLane() {
+	// Given
+	release, started := make(chan struct{}), make(chan struct{}, 1)
+	var handled atomic.Int32
+	mockiator.OnPublish(func(request OrderCreated, params ...any) {
+		if request.Seq == 0 {
+			started <- struct{}{}
+			<-release
+		}
+		handled.Add(1)
+	})
+	s.Nil(godiator.Publish(OrderCreated{OrderID: "order-1"}))
+	<-started
+	for seq := 1; seq <= 1024; seq++ {
+		s.Nil(godiator.Publish(OrderCreated{OrderID: "order-1", Seq: seq}))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// When
+	fullErr := godiator.Publish(OrderCreated{OrderID: "order-1", Seq: 1025}, ctx)
+	otherKeyErr := godiator.Publish(OrderCreated{OrderID: "order-2", Seq: 1})
+	close(release)
+
+	// Then
+	s.ErrorIs(fullErr, context.DeadlineExceeded)
+	s.Nil(otherKeyErr)
+	s.Eventually(func() bool { return handled.Load() == 1026 }, time.Second, time.Millisecond)
+}
@@ -0,0 +1,159 @@
+// Test Suite for Authorization Pipeline
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/core/interfaces"
+	"github.com/baranius/godiator/pipeline"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	User struct {
+		ID          int
+		Permissions []string
+	}
+	DeleteOrderCommand struct {
+		OwnerID int
+	}
+	GetOrderQuery       struct{}
+	ArchiveOrderCommand struct{}
+)
+
+var errNotOwner = errors.New("only the owner can delete an order")
+
+func (u User) HasPermission(permission string) bool {
+	return slices.Contains(u.Permissions, permission)
+}
+
+func (c DeleteOrderCommand) RequiresPermission() []string  { return []string{"orders:delete"} }
+func (c ArchiveOrderCommand) RequiresPermission() []string { return nil }
+
+type AuthorizationTestSuite struct {
+	suite.Suite
+	terminal *terminalPipeline
+}
+
+// Run Authorization Test Suite
+func TestAuthorizationTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthorizationTestSuite))
+}
+
+func (s *AuthorizationTestSuite) SetupTest() {
+	s.terminal = &terminalPipeline{handle: func(request any, params ...any) (any, error) {
+		return "response", nil
+	}}
+}
+
+func (s *AuthorizationTestSuite) TearDownTest() {
+	godiator.UnregisterPolicies[DeleteOrderCommand]()
+	godiator.UnregisterPolicies[GetOrderQuery]()
+}
+
+func (s *AuthorizationTestSuite) newAuthorization(opts ...pipeline.AuthorizationOption) *pipeline.Authorization {
+	p := pipeline.NewAuthorization(opts...)
+	p.SetNext(s.terminal)
+	return p
+}
+
+// Test principals holding the required permissions are allowed
+func (s *AuthorizationTestSuite) TestAuthorization_RequiredPermissions() {
+	// Given
+	p := s.newAuthorization()
+	admin := User{ID: 1, Permissions: []string{"orders:delete"}}
+	ctx := godiator.ContextWithPrincipal(context.Background(), admin)
+
+	// When
+	response, err := p.Handle(DeleteOrderCommand{OwnerID: 2}, ctx)
+
+	// Then
+	s.Nil(err)
+	s.Equal("response", response)
+}
+
+// Test principals missing a required permission are forbidden
+func (s *AuthorizationTestSuite) TestAuthorization_MissingPermission() {
+	// Given
+	p := s.newAuthorization()
+
+	// When
+	response, err := p.Handle(DeleteOrderCommand{}, godiator.ContextWithPrincipal(context.Background(), User{ID: 1}))
+
+	// Then
+	s.Nil(response)
+	s.ErrorIs(err, pipeline.ErrForbidden)
+	s.EqualError(err, `request "tests.DeleteOrderCommand" is forbidden: missing permission "orders:delete"`)
+	s.Equal(int32(0), s.terminal.calls.Load())
+}
+
+// Test requests without a principal are forbidden when permissions are required
+func (s *AuthorizationTestSuite) TestAuthorization_NoPrincipal() {
+	// When
+	_, err := s.newAuthorization().Handle(DeleteOrderCommand{}, context.Background())
+
+	// Then
+	var forbiddenErr *pipeline.ForbiddenError
+	s.True(errors.As(err, &forbiddenErr))
+	s.Equal("no principal", forbiddenErr.Reason)
+}
+
+// Test params implementing Principal outside of the context are not taken for the principal
+func (s *AuthorizationTestSuite) TestAuthorization_PrincipalParam() {
+	// When
+	_, err := s.newAuthorization().Handle(DeleteOrderCommand{}, User{ID: 1, Permissions: []string{"orders:delete"}})
+
+	// Then
+	var forbiddenErr *pipeline.ForbiddenError
+	s.True(errors.As(err, &forbiddenErr))
+	s.Equal("no principal", forbiddenErr.Reason)
+}
+
+// Test registered policies are evaluated against the principal
+func (s *AuthorizationTestSuite) TestAuthorization_Policies() {
+	// Given
+	godiator.RegisterPolicy(func(ctx context.Context, principal interfaces.Principal, request DeleteOrderCommand) error {
+		if principal.(User).ID != request.OwnerID {
+			return errNotOwner
+		}
+		return nil
+	})
+	p := s.newAuthorization()
+	user := godiator.ContextWithPrincipal(context.Background(), User{ID: 1, Permissions: []string{"orders:delete"}})
+
+	// When
+	_, ownErr := p.Handle(DeleteOrderCommand{OwnerID: 1}, user)
+	_, otherErr := p.Handle(DeleteOrderCommand{OwnerID: 2}, user)
+
+	// Then
+	s.Nil(ownErr)
+	s.EqualError(otherErr, `request "tests.DeleteOrderCommand" is forbidden: only the owner can delete an order`)
+	s.ErrorIs(otherErr, pipeline.ErrForbidden)
+	s.ErrorIs(otherErr, errNotOwner)
+}
+
+// Test unregistered request types are allowed unless default deny is enabled
+func (s *AuthorizationTestSuite) TestAuthorization_DefaultDeny() {
+	// When
+	_, allowErr := s.newAuthorization().Handle(GetOrderQuery{})
+	_, denyErr := s.newAuthorization(pipeline.WithDefaultDeny()).Handle(GetOrderQuery{})
+
+	// Then
+	s.Nil(allowErr)
+	s.ErrorIs(denyErr, pipeline.ErrForbidden)
+}
+
+// Test requests declaring no permission are denied by default deny
+func (s *AuthorizationTestSuite) TestAuthorization_NoPermissionsDeclared() {
+	// When
+	_, allowErr := s.newAuthorization().Handle(ArchiveOrderCommand{})
+	_, denyErr := s.newAuthorization(pipeline.WithDefaultDeny()).Handle(ArchiveOrderCommand{})
+
+	// Then
+	s.Nil(allowErr)
+	s.EqualError(denyErr, `request "tests.ArchiveOrderCommand" is forbidden: no authorization policy`)
+}
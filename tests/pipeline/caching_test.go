@@ -0,0 +1,207 @@
+// Test Suite for Caching Pipeline
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/pipeline"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	CachedRequest struct {
+		SKU string
+		ttl time.Duration
+	}
+	UncachedRequest struct {
+		SKU string
+	}
+	CachedStockRequest struct {
+		SKU string
+	}
+	ProductChangedEvent struct {
+		SKU string
+	}
+)
+
+func (r CachedRequest) CacheKey() string    { return "product:" + r.SKU }
+func (r CachedRequest) TTL() time.Duration  { return r.ttl }
+func (r CachedRequest) CacheTags() []string { return []string{"catalog"} }
+
+func (r CachedStockRequest) CacheKey() string   { return "product:" + r.SKU }
+func (r CachedStockRequest) TTL() time.Duration { return time.Minute }
+
+type CachingTestSuite struct {
+	suite.Suite
+	cache    *pipeline.LRUCache
+	caching  *pipeline.Caching
+	terminal *terminalPipeline
+}
+
+// Run Caching Test Suite
+func TestCachingTestSuite(t *testing.T) {
+	suite.Run(t, new(CachingTestSuite))
+}
+
+func (s *CachingTestSuite) SetupTest() {
+	s.cache = pipeline.NewLRUCache(10)
+	s.caching = pipeline.NewCaching(s.cache)
+	s.terminal = &terminalPipeline{handle: func(request any, params ...any) (any, error) {
+		return "response", nil
+	}}
+	s.caching.SetNext(s.terminal)
+}
+
+// Test Cacheable requests are served from the cache
+func (s *CachingTestSuite) TestCaching_ServesFromCache() {
+	// Given
+	request := CachedRequest{SKU: "a", ttl: time.Minute}
+
+	// When
+	first, firstErr := s.caching.Handle(request)
+	second, secondErr := s.caching.Handle(request)
+
+	// Then
+	s.Nil(firstErr)
+	s.Nil(secondErr)
+	s.Equal("response", first)
+	s.Equal("response", second)
+	s.Equal(int32(1), s.terminal.calls.Load())
+}
+
+// Test request types sharing a cache key do not share entries
+func (s *CachingTestSuite) TestCaching_KeyedByRequestType() {
+	// Given
+	s.terminal.handle = func(request any, params ...any) (any, error) {
+		return reflect.TypeOf(request).Name(), nil
+	}
+
+	// When
+	product, _ := s.caching.Handle(CachedRequest{SKU: "a", ttl: time.Minute})
+	stock, _ := s.caching.Handle(CachedStockRequest{SKU: "a"})
+
+	// Then
+	s.Equal("CachedRequest", product)
+	s.Equal("CachedStockRequest", stock)
+	s.Equal(2, s.cache.Len())
+}
+
+// Test other requests bypass the cache
+func (s *CachingTestSuite) TestCaching_PassThrough() {
+	// When
+	_, _ = s.caching.Handle(UncachedRequest{SKU: "a"})
+	_, _ = s.caching.Handle(UncachedRequest{SKU: "a"})
+
+	// Then
+	s.Equal(int32(2), s.terminal.calls.Load())
+	s.Equal(0, s.cache.Len())
+}
+
+// Test errors are not cached
+func (s *CachingTestSuite) TestCaching_ErrorsAreNotCached() {
+	// Given
+	s.terminal.handle = func(request any, params ...any) (any, error) {
+		return nil, errors.New("failed")
+	}
+
+	// When
+	_, err := s.caching.Handle(CachedRequest{SKU: "a", ttl: time.Minute})
+
+	// Then
+	s.EqualError(err, "failed")
+	s.Equal(0, s.cache.Len())
+}
+
+// Test entries expire after their TTL
+func (s *CachingTestSuite) TestCaching_Expiry() {
+	// Given
+	request := CachedRequest{SKU: "a", ttl: 20 * time.Millisecond}
+	_, _ = s.caching.Handle(request)
+
+	// When
+	time.Sleep(30 * time.Millisecond)
+	_, _ = s.caching.Handle(request)
+
+	// Then
+	s.Equal(int32(2), s.terminal.calls.Load())
+}
+
+// Test the least recently used entry is evicted
+func (s *CachingTestSuite) TestLRUCache_Eviction() {
+	// Given
+	cache := pipeline.NewLRUCache(2)
+	cache.Set("a", 1, time.Minute)
+	cache.Set("b", 2, time.Minute)
+	_, _ = cache.Get("a")
+
+	// When
+	cache.Set("c", 3, time.Minute)
+
+	// Then
+	_, foundA := cache.Get("a")
+	_, foundB := cache.Get("b")
+	s.True(foundA)
+	s.False(foundB)
+	s.Equal(2, cache.Len())
+}
+
+// Test publishing an event invalidates cached entries
+func (s *CachingTestSuite) TestCaching_InvalidateOnPublish() {
+	// Given
+	stop := godiator.InvalidateCacheOn(s.cache, func(e ProductChangedEvent) godiator.CacheInvalidation {
+		return godiator.CacheInvalidation{Keys: []string{pipeline.CacheEntryKey(CachedRequest{SKU: e.SKU})}}
+	})
+	defer stop()
+	request := CachedRequest{SKU: "a", ttl: time.Minute}
+	_, _ = s.caching.Handle(request)
+	s.cache.Set("listing", "all", time.Minute, "catalog")
+	_, cached := s.cache.Get(pipeline.CacheEntryKey(request))
+
+	// When
+	godiator.Publish(ProductChangedEvent{SKU: "a"})
+
+	// Then
+	_, foundProduct := s.cache.Get(pipeline.CacheEntryKey(request))
+	_, foundListing := s.cache.Get("listing")
+	s.True(cached)
+	s.False(foundProduct)
+	s.True(foundListing)
+}
+
+// Test a removed invalidation no longer runs
+func (s *CachingTestSuite) TestCaching_StopInvalidation() {
+	// Given
+	stop := godiator.InvalidateCacheOn(s.cache, func(e ProductChangedEvent) godiator.CacheInvalidation {
+		return godiator.CacheInvalidation{Tags: []string{"catalog"}}
+	})
+	s.cache.Set("listing", "all", time.Minute, "catalog")
+
+	// When
+	stopped := stop()
+	stoppedAgain := stop()
+	godiator.Publish(ProductChangedEvent{SKU: "a"})
+
+	// Then
+	_, foundListing := s.cache.Get("listing")
+	s.True(stopped)
+	s.False(stoppedAgain)
+	s.True(foundListing)
+}
+
+// Test deleting a tag removes every entry associated with it
+func (s *CachingTestSuite) TestLRUCache_DeleteTags() {
+	// Given
+	s.cache.Set("a", 1, time.Minute, "catalog")
+	s.cache.Set("b", 2, time.Minute, "catalog", "featured")
+	s.cache.Set("c", 3, time.Minute)
+
+	// When
+	s.cache.DeleteTags("catalog")
+
+	// Then
+	s.Equal(1, s.cache.Len())
+}
@@ -0,0 +1,279 @@
+// Test Suite for Circuit Breaker Pipeline
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/pipeline"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	FlakyRequest  struct{}
+	StableRequest struct{}
+	QueuedRequest struct{}
+)
+
+// Records the state changes published during a single test
+type CircuitChangeSubscriber struct {
+	mu      sync.Mutex
+	changes []pipeline.CircuitStateChanged
+}
+
+func (c *CircuitChangeSubscriber) Handle(change pipeline.CircuitStateChanged, params ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.changes = append(c.changes, change)
+}
+
+func (c *CircuitChangeSubscriber) Count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.changes)
+}
+
+func (c *CircuitChangeSubscriber) CountOf(requestType string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count := 0
+	for _, change := range c.changes {
+		if change.RequestType == requestType {
+			count++
+		}
+	}
+	return count
+}
+
+// Holds the worker of the event queue until released
+type BlockedCircuitChangeSubscriber struct {
+	release chan struct{}
+}
+
+func (c *BlockedCircuitChangeSubscriber) Handle(change pipeline.CircuitStateChanged, params ...any) {
+	<-c.release
+}
+
+// Counts the state changes published
+type CircuitChangeObserver struct {
+	godiator.BaseObserver
+	published atomic.Int32
+}
+
+func (o *CircuitChangeObserver) BeforePublish(event any, params ...any) {
+	if _, ok := event.(pipeline.CircuitStateChanged); ok {
+		o.published.Add(1)
+	}
+}
+
+type CircuitBreakerTestSuite struct {
+	suite.Suite
+	breaker    *pipeline.CircuitBreaker
+	clock      *fakeClock
+	terminal   *terminalPipeline
+	failing    bool
+	subscriber *CircuitChangeSubscriber
+}
+
+// Run Circuit Breaker Test Suite
+func TestCircuitBreakerTestSuite(t *testing.T) {
+	suite.Run(t, new(CircuitBreakerTestSuite))
+}
+
+func (s *CircuitBreakerTestSuite) SetupTest() {
+	s.failing = true
+	s.terminal = &terminalPipeline{handle: func(request any, params ...any) (any, error) {
+		if s.failing {
+			return nil, errors.New("downstream unavailable")
+		}
+		return "response", nil
+	}}
+	s.clock = &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s.breaker = pipeline.NewCircuitBreaker(
+		pipeline.WithFailureThreshold(3),
+		pipeline.WithCoolDown(time.Minute),
+		pipeline.WithCircuitClock(s.clock),
+	)
+	s.breaker.SetNext(s.terminal)
+
+	s.subscriber = &CircuitChangeSubscriber{}
+	godiator.RegisterSubscriber[pipeline.CircuitStateChanged](s.subscriber)
+}
+
+func (s *CircuitBreakerTestSuite) TearDownTest() {
+	godiator.UnregisterSubscriber[pipeline.CircuitStateChanged]()
+}
+
+func (s *CircuitBreakerTestSuite) fail(times int) {
+	for i := 0; i < times; i++ {
+		_, _ = s.breaker.Handle(FlakyRequest{})
+	}
+}
+
+// Test consecutive failures open the circuit
+func (s *CircuitBreakerTestSuite) TestCircuitBreaker_Opens() {
+	// Given
+	s.fail(3)
+
+	// When
+	response, err := s.breaker.Handle(FlakyRequest{})
+
+	// Then
+	s.Nil(response)
+	s.ErrorIs(err, pipeline.ErrCircuitOpen)
+	s.EqualError(err, `circuit breaker is open for "tests.FlakyRequest"`)
+	s.Equal(int32(3), s.terminal.calls.Load())
+	s.Equal(pipeline.CircuitOpen, s.breaker.State(FlakyRequest{}))
+	s.Equal(pipeline.CircuitClosed, s.breaker.State(StableRequest{}))
+}
+
+// Test a success resets the consecutive failure count
+func (s *CircuitBreakerTestSuite) TestCircuitBreaker_SuccessResetsFailures() {
+	// Given
+	s.fail(2)
+	s.failing = false
+	_, _ = s.breaker.Handle(FlakyRequest{})
+	s.failing = true
+
+	// When
+	s.fail(2)
+
+	// Then
+	s.Equal(pipeline.CircuitClosed, s.breaker.State(FlakyRequest{}))
+}
+
+// Test a successful trial request closes the circuit
+func (s *CircuitBreakerTestSuite) TestCircuitBreaker_HalfOpenCloses() {
+	// Given
+	s.fail(3)
+	s.clock.Advance(time.Minute)
+	s.failing = false
+
+	// When
+	response, err := s.breaker.Handle(FlakyRequest{})
+
+	// Then
+	s.Nil(err)
+	s.Equal("response", response)
+	s.Equal(pipeline.CircuitClosed, s.breaker.State(FlakyRequest{}))
+
+	s.Eventually(func() bool { return s.subscriber.Count() == 3 }, time.Second, 10*time.Millisecond)
+}
+
+// Test a failed trial request opens the circuit again
+func (s *CircuitBreakerTestSuite) TestCircuitBreaker_HalfOpenReopens() {
+	// Given
+	s.fail(3)
+	s.clock.Advance(time.Minute)
+
+	// When
+	s.fail(1)
+
+	// Then
+	s.Equal(pipeline.CircuitOpen, s.breaker.State(FlakyRequest{}))
+	_, err := s.breaker.Handle(FlakyRequest{})
+	s.ErrorIs(err, pipeline.ErrCircuitOpen)
+}
+
+// Test the circuit stays open until the cool-down has elapsed
+func (s *CircuitBreakerTestSuite) TestCircuitBreaker_CoolDown() {
+	// Given
+	s.fail(3)
+	s.failing = false
+
+	// When
+	s.clock.Advance(time.Minute - time.Second)
+	_, err := s.breaker.Handle(FlakyRequest{})
+
+	// Then
+	s.ErrorIs(err, pipeline.ErrCircuitOpen)
+	s.Equal(int32(3), s.terminal.calls.Load())
+}
+
+// Test requests cancelled by their caller are not counted as failures
+func (s *CircuitBreakerTestSuite) TestCircuitBreaker_CancelledRequests() {
+	// Given
+	s.terminal.handle = func(request any, params ...any) (any, error) {
+		return nil, context.Canceled
+	}
+
+	// When
+	s.fail(3)
+
+	// Then
+	s.Equal(pipeline.CircuitClosed, s.breaker.State(FlakyRequest{}))
+}
+
+// Test state changes do not block requests while the event queue is full
+func (s *CircuitBreakerTestSuite) TestCircuitBreaker_FullEventQueue() {
+	// Given
+	godiator.UnregisterSubscriber[pipeline.CircuitStateChanged]()
+	blocked := &BlockedCircuitChangeSubscriber{release: make(chan struct{})}
+	godiator.RegisterSubscriber[pipeline.CircuitStateChanged](blocked)
+	godiator.RegisterSubscriber[pipeline.CircuitStateChanged](s.subscriber)
+	godiator.UseEventQueue(godiator.EventQueueConfig{Capacity: 1, Workers: 1, Overflow: godiator.OverflowBlock})
+	defer godiator.DisableEventQueue()
+
+	// When
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 9; i++ {
+			_, _ = s.breaker.Handle(QueuedRequest{})
+			if i%3 == 2 {
+				s.clock.Advance(time.Minute)
+			}
+		}
+	}()
+
+	// Then
+	s.Eventually(func() bool {
+		select {
+		case <-done:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+	s.Equal(pipeline.CircuitOpen, s.breaker.State(QueuedRequest{}))
+
+	close(blocked.release)
+	s.Eventually(func() bool { return s.subscriber.CountOf("tests.QueuedRequest") == 5 }, time.Second, 10*time.Millisecond)
+}
+
+// Test errors rejected by the failure filter do not open the circuit
+func (s *CircuitBreakerTestSuite) TestCircuitBreaker_FailureFilter() {
+	// Given
+	s.breaker = pipeline.NewCircuitBreaker(
+		pipeline.WithFailureThreshold(1),
+		pipeline.WithFailureFilter(func(err error) bool { return false }),
+	)
+	s.breaker.SetNext(s.terminal)
+
+	// When
+	s.fail(3)
+
+	// Then
+	s.Equal(pipeline.CircuitClosed, s.breaker.State(FlakyRequest{}))
+}
+
+// Test state changes are not published while nobody subscribes to them
+func (s *CircuitBreakerTestSuite) TestCircuitBreaker_NoSubscribers() {
+	// Given
+	godiator.UnregisterSubscriber[pipeline.CircuitStateChanged]()
+	observer := &CircuitChangeObserver{}
+	godiator.RegisterObserver(observer)
+	defer godiator.UnregisterObserver(observer)
+
+	// When
+	s.fail(3)
+
+	// Then
+	s.Equal(pipeline.CircuitOpen, s.breaker.State(FlakyRequest{}))
+	s.Equal(int32(0), observer.published.Load())
+}
@@ -0,0 +1,19 @@
+package tests
+
+import (
+	"sync/atomic"
+
+	"github.com/baranius/godiator/pipeline"
+)
+
+// terminalPipeline stands in for the handler at the end of a pipeline chain
+type terminalPipeline struct {
+	pipeline.BasePipeline
+	calls  atomic.Int32
+	handle func(request any, params ...any) (any, error)
+}
+
+func (p *terminalPipeline) Handle(request any, params ...any) (any, error) {
+	p.calls.Add(1)
+	return p.handle(request, params...)
+}
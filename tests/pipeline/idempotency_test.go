@@ -0,0 +1,247 @@
+// Test Suite for Idempotency Pipeline
+package tests
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator/pipeline"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	ChargePaymentCommand struct {
+		PaymentID string
+	}
+	ChargePaymentResponse struct {
+		TransactionID string
+	}
+	ListPaymentsQuery struct{}
+)
+
+func (c ChargePaymentCommand) IdempotencyKey() string { return c.PaymentID }
+
+// Reads nothing and fails every write
+type unavailableIdempotencyStore struct{}
+
+func (unavailableIdempotencyStore) Get(key string) (pipeline.IdempotencyRecord, bool, error) {
+	return pipeline.IdempotencyRecord{}, false, nil
+}
+
+func (unavailableIdempotencyStore) Put(key string, record pipeline.IdempotencyRecord) error {
+	return errors.New("disk full")
+}
+
+type IdempotencyTestSuite struct {
+	suite.Suite
+	terminal *terminalPipeline
+}
+
+// Run Idempotency Test Suite
+func TestIdempotencyTestSuite(t *testing.T) {
+	gob.Register(ChargePaymentResponse{})
+	suite.Run(t, new(IdempotencyTestSuite))
+}
+
+func (s *IdempotencyTestSuite) SetupTest() {
+	s.terminal = &terminalPipeline{handle: func(request any, params ...any) (any, error) {
+		return ChargePaymentResponse{TransactionID: "tx-" + request.(ChargePaymentCommand).PaymentID}, nil
+	}}
+}
+
+func (s *IdempotencyTestSuite) newIdempotency(store pipeline.IdempotencyStore) *pipeline.Idempotency {
+	p := pipeline.NewIdempotency(store)
+	p.SetNext(s.terminal)
+	return p
+}
+
+// Test repeated commands replay the stored response
+func (s *IdempotencyTestSuite) TestIdempotency_ReplaysResponse() {
+	// Given
+	p := s.newIdempotency(pipeline.NewMemoryIdempotencyStore())
+
+	// When
+	first, firstErr := p.Handle(ChargePaymentCommand{PaymentID: "1"})
+	second, secondErr := p.Handle(ChargePaymentCommand{PaymentID: "1"})
+	other, _ := p.Handle(ChargePaymentCommand{PaymentID: "2"})
+
+	// Then
+	s.Nil(firstErr)
+	s.Nil(secondErr)
+	s.Equal(ChargePaymentResponse{TransactionID: "tx-1"}, first)
+	s.Equal(first, second)
+	s.Equal(ChargePaymentResponse{TransactionID: "tx-2"}, other)
+	s.Equal(int32(2), s.terminal.calls.Load())
+}
+
+// Test failed commands are executed again by default
+func (s *IdempotencyTestSuite) TestIdempotency_RetriesError() {
+	// Given
+	failing := true
+	handle := s.terminal.handle
+	s.terminal.handle = func(request any, params ...any) (any, error) {
+		if failing {
+			return nil, errors.New("gateway timeout")
+		}
+		return handle(request, params...)
+	}
+	p := s.newIdempotency(pipeline.NewMemoryIdempotencyStore())
+
+	// When
+	_, firstErr := p.Handle(ChargePaymentCommand{PaymentID: "1"})
+	failing = false
+	second, secondErr := p.Handle(ChargePaymentCommand{PaymentID: "1"})
+	third, _ := p.Handle(ChargePaymentCommand{PaymentID: "1"})
+
+	// Then
+	s.EqualError(firstErr, "gateway timeout")
+	s.Nil(secondErr)
+	s.Equal(ChargePaymentResponse{TransactionID: "tx-1"}, second)
+	s.Equal(second, third)
+	s.Equal(int32(2), s.terminal.calls.Load())
+}
+
+// Test errors accepted by the replay filter are stored and replayed
+func (s *IdempotencyTestSuite) TestIdempotency_ReplaysError() {
+	// Given
+	declined := errors.New("card declined")
+	s.terminal.handle = func(request any, params ...any) (any, error) {
+		if request.(ChargePaymentCommand).PaymentID == "1" {
+			return nil, declined
+		}
+		return nil, errors.New("gateway timeout")
+	}
+	p := pipeline.NewIdempotency(pipeline.NewMemoryIdempotencyStore(),
+		pipeline.WithErrorReplay(func(err error) bool { return errors.Is(err, declined) }))
+	p.SetNext(s.terminal)
+
+	// When
+	_, firstErr := p.Handle(ChargePaymentCommand{PaymentID: "1"})
+	_, secondErr := p.Handle(ChargePaymentCommand{PaymentID: "1"})
+	_, _ = p.Handle(ChargePaymentCommand{PaymentID: "2"})
+	_, _ = p.Handle(ChargePaymentCommand{PaymentID: "2"})
+
+	// Then
+	s.EqualError(firstErr, "card declined")
+	s.EqualError(secondErr, "card declined")
+	s.Equal(int32(3), s.terminal.calls.Load())
+}
+
+// Test failing to save the outcome still returns the response
+func (s *IdempotencyTestSuite) TestIdempotency_StoreFailure() {
+	// Given
+	p := s.newIdempotency(&unavailableIdempotencyStore{})
+
+	// When
+	response, err := p.Handle(ChargePaymentCommand{PaymentID: "1"})
+
+	// Then
+	s.Nil(err)
+	s.Equal(ChargePaymentResponse{TransactionID: "tx-1"}, response)
+}
+
+// Test concurrent duplicates wait for the in-progress execution
+func (s *IdempotencyTestSuite) TestIdempotency_ConcurrentDuplicates() {
+	// Given
+	release := make(chan struct{})
+	handle := s.terminal.handle
+	s.terminal.handle = func(request any, params ...any) (any, error) {
+		<-release
+		return handle(request, params...)
+	}
+	p := s.newIdempotency(pipeline.NewMemoryIdempotencyStore())
+
+	// When
+	var wg sync.WaitGroup
+	responses := make([]any, 5)
+	for i := range responses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i], _ = p.Handle(ChargePaymentCommand{PaymentID: "1"})
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// Then
+	s.Equal(int32(1), s.terminal.calls.Load())
+	for _, response := range responses {
+		s.Equal(ChargePaymentResponse{TransactionID: "tx-1"}, response)
+	}
+}
+
+// Test a duplicate stops waiting for the in-progress execution once its context is done
+func (s *IdempotencyTestSuite) TestIdempotency_CancelledDuplicate() {
+	// Given
+	started, release := make(chan struct{}), make(chan struct{})
+	handle := s.terminal.handle
+	s.terminal.handle = func(request any, params ...any) (any, error) {
+		close(started)
+		<-release
+		return handle(request, params...)
+	}
+	p := s.newIdempotency(pipeline.NewMemoryIdempotencyStore())
+	first := make(chan any, 1)
+	go func() {
+		response, _ := p.Handle(ChargePaymentCommand{PaymentID: "1"})
+		first <- response
+	}()
+	<-started
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// When
+	response, err := p.Handle(ChargePaymentCommand{PaymentID: "1"}, ctx)
+	close(release)
+
+	// Then
+	s.ErrorIs(err, context.Canceled)
+	s.Nil(response)
+	s.Equal(ChargePaymentResponse{TransactionID: "tx-1"}, <-first)
+	s.Equal(int32(1), s.terminal.calls.Load())
+}
+
+// Test other requests are passed through
+func (s *IdempotencyTestSuite) TestIdempotency_PassThrough() {
+	// Given
+	s.terminal.handle = func(request any, params ...any) (any, error) {
+		return "payments", nil
+	}
+	p := s.newIdempotency(pipeline.NewMemoryIdempotencyStore())
+
+	// When
+	_, _ = p.Handle(ListPaymentsQuery{})
+	_, _ = p.Handle(ListPaymentsQuery{})
+
+	// Then
+	s.Equal(int32(2), s.terminal.calls.Load())
+}
+
+// Test the file store keeps records across instances
+func (s *IdempotencyTestSuite) TestFileIdempotencyStore_Persists() {
+	// Given
+	dir := s.T().TempDir()
+	store, err := pipeline.NewFileIdempotencyStore(dir)
+	s.Require().Nil(err)
+	_, _ = s.newIdempotency(store).Handle(ChargePaymentCommand{PaymentID: "1"})
+
+	// When
+	reopened, err := pipeline.NewFileIdempotencyStore(dir)
+	s.Require().Nil(err)
+	response, err := s.newIdempotency(reopened).Handle(ChargePaymentCommand{PaymentID: "1"})
+
+	// Then
+	s.Nil(err)
+	s.Equal(ChargePaymentResponse{TransactionID: "tx-1"}, response)
+	s.Equal(int32(1), s.terminal.calls.Load())
+
+	_, found, err := reopened.Get("tests.ChargePaymentCommand:2")
+	s.Nil(err)
+	s.False(found)
+}
@@ -0,0 +1,187 @@
+// Test Suite for Logging Pipeline
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/baranius/godiator/pipeline"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	LoginRequest struct {
+		Username string
+		Password string `godiator:"redact"`
+		Device   LoginDevice
+	}
+	LoginDevice struct {
+		Name  string
+		Token string `godiator:"redact"`
+	}
+	LoginResponse struct {
+		SessionID string
+	}
+	PaymentRequest struct {
+		CardNumber string
+	}
+	HealthCheckRequest struct{}
+)
+
+func (r PaymentRequest) Redact() any {
+	return PaymentRequest{CardNumber: "**** " + r.CardNumber[len(r.CardNumber)-4:]}
+}
+
+type LoggingTestSuite struct {
+	suite.Suite
+	output   bytes.Buffer
+	logger   *slog.Logger
+	terminal *terminalPipeline
+}
+
+// Run Logging Test Suite
+func TestLoggingTestSuite(t *testing.T) {
+	suite.Run(t, new(LoggingTestSuite))
+}
+
+func (s *LoggingTestSuite) SetupTest() {
+	s.output.Reset()
+	s.logger = slog.New(slog.NewJSONHandler(&s.output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	s.terminal = &terminalPipeline{handle: func(request any, params ...any) (any, error) {
+		return LoginResponse{SessionID: "session"}, nil
+	}}
+}
+
+func (s *LoggingTestSuite) newLogging(opts ...pipeline.LoggingOption) *pipeline.Logging {
+	p := pipeline.NewLogging(s.logger, opts...)
+	p.SetNext(s.terminal)
+	return p
+}
+
+// records decodes the JSON records written to the output
+func (s *LoggingTestSuite) records() []map[string]any {
+	var records []map[string]any
+	decoder := json.NewDecoder(&s.output)
+	for decoder.More() {
+		var record map[string]any
+		s.Require().NoError(decoder.Decode(&record))
+		records = append(records, record)
+	}
+	return records
+}
+
+// Test successful requests are logged with their type, duration and outcome
+func (s *LoggingTestSuite) TestLogging_Success() {
+	// Given
+	p := s.newLogging()
+
+	// When
+	response, err := p.Handle(LoginRequest{Username: "john"})
+
+	// Then
+	s.Nil(err)
+	s.Equal(LoginResponse{SessionID: "session"}, response)
+	records := s.records()
+	s.Len(records, 1)
+	s.Equal("INFO", records[0]["level"])
+	s.Equal("request handled", records[0]["msg"])
+	s.Equal("tests.LoginRequest", records[0]["request_type"])
+	s.Equal("success", records[0]["outcome"])
+	s.Contains(records[0], "duration")
+	s.NotContains(records[0], "request")
+}
+
+// Test failed requests are logged at error level with the error
+func (s *LoggingTestSuite) TestLogging_Failure() {
+	// Given
+	s.terminal.handle = func(request any, params ...any) (any, error) {
+		return nil, errors.New("invalid credentials")
+	}
+	p := s.newLogging(pipeline.LogSampleRateFor[LoginRequest](0))
+
+	// When
+	_, err := p.Handle(LoginRequest{Username: "john"})
+
+	// Then
+	s.EqualError(err, "invalid credentials")
+	records := s.records()
+	s.Len(records, 1)
+	s.Equal("ERROR", records[0]["level"])
+	s.Equal("error", records[0]["outcome"])
+	s.Equal("invalid credentials", records[0]["error"])
+}
+
+// Test payloads are logged with tagged fields redacted
+func (s *LoggingTestSuite) TestLogging_RedactsTaggedFields() {
+	// Given
+	p := s.newLogging(pipeline.WithPayloads())
+
+	// When
+	_, err := p.Handle(LoginRequest{Username: "john", Password: "secret", Device: LoginDevice{Name: "phone", Token: "token"}})
+
+	// Then
+	s.Nil(err)
+	records := s.records()
+	s.Len(records, 1)
+	s.Equal(map[string]any{
+		"Username": "john",
+		"Password": "[REDACTED]",
+		"Device":   map[string]any{"Name": "phone", "Token": "[REDACTED]"},
+	}, records[0]["request"])
+	s.Equal(map[string]any{"SessionID": "session"}, records[0]["response"])
+	s.NotContains(s.output.String(), "secret")
+}
+
+// Test payloads implementing Redactor are logged in their redacted form
+func (s *LoggingTestSuite) TestLogging_Redactor() {
+	// Given
+	p := s.newLogging(pipeline.WithPayloads())
+
+	// When
+	_, err := p.Handle(&PaymentRequest{CardNumber: "4111111111111111"})
+
+	// Then
+	s.Nil(err)
+	records := s.records()
+	s.Len(records, 1)
+	s.Equal(map[string]any{"CardNumber": "**** 1111"}, records[0]["request"])
+}
+
+// Test levels can be set per request type
+func (s *LoggingTestSuite) TestLogging_LevelPerRequestType() {
+	// Given
+	s.logger = slog.New(slog.NewJSONHandler(&s.output, nil))
+	p := s.newLogging(pipeline.LogLevelFor[HealthCheckRequest](slog.LevelDebug))
+
+	// When
+	p.Handle(HealthCheckRequest{})
+	p.Handle(LoginRequest{})
+
+	// Then
+	records := s.records()
+	s.Len(records, 1)
+	s.Equal("tests.LoginRequest", records[0]["request_type"])
+	s.Equal(int32(2), s.terminal.calls.Load())
+}
+
+// Test successful requests are sampled per request type
+func (s *LoggingTestSuite) TestLogging_Sampling() {
+	// Given
+	p := s.newLogging(pipeline.LogSampleRateFor[HealthCheckRequest](0), pipeline.LogSampleRateFor[LoginRequest](1))
+
+	// When
+	for range 10 {
+		p.Handle(HealthCheckRequest{})
+		p.Handle(LoginRequest{})
+	}
+
+	// Then
+	records := s.records()
+	s.Len(records, 10)
+	for _, record := range records {
+		s.Equal("tests.LoginRequest", record["request_type"])
+	}
+}
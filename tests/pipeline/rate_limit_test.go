@@ -0,0 +1,224 @@
+// Test Suite for Rate Limit Pipeline
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator/pipeline"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	SearchRequest struct {
+		Tenant string
+	}
+	ExportRequest struct{}
+)
+
+func (r SearchRequest) RateLimitKey() string { return r.Tenant }
+
+// fakeClock only moves forward when advanced
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, waiter := range c.waiters {
+		if !waiter.at.After(c.now) {
+			waiter.ch <- c.now
+		} else {
+			pending = append(pending, waiter)
+		}
+	}
+	c.waiters = pending
+}
+
+func (c *fakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+type RateLimitTestSuite struct {
+	suite.Suite
+	clock    *fakeClock
+	terminal *terminalPipeline
+}
+
+// Run Rate Limit Test Suite
+func TestRateLimitTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitTestSuite))
+}
+
+func (s *RateLimitTestSuite) SetupTest() {
+	s.clock = &fakeClock{now: time.Unix(0, 0)}
+	s.terminal = &terminalPipeline{handle: func(request any, params ...any) (any, error) {
+		return "response", nil
+	}}
+}
+
+func (s *RateLimitTestSuite) newRateLimit(limit pipeline.Limit, opts ...pipeline.RateLimitOption) *pipeline.RateLimit {
+	p := pipeline.NewRateLimit(limit, append(opts, pipeline.WithClock(s.clock))...)
+	p.SetNext(s.terminal)
+	return p
+}
+
+// Test requests beyond the burst are rejected until tokens are refilled
+func (s *RateLimitTestSuite) TestRateLimit_Reject() {
+	// Given
+	p := s.newRateLimit(pipeline.Limit{Rate: 2, Burst: 2})
+	_, _ = p.Handle(ExportRequest{})
+	_, _ = p.Handle(ExportRequest{})
+
+	// When
+	response, err := p.Handle(ExportRequest{})
+
+	// Then
+	s.Nil(response)
+	s.ErrorIs(err, pipeline.ErrRateLimited)
+	var rateLimitedErr *pipeline.RateLimitedError
+	s.True(errors.As(err, &rateLimitedErr))
+	s.Equal(500*time.Millisecond, rateLimitedErr.RetryAfter)
+
+	s.clock.Advance(500 * time.Millisecond)
+	_, err = p.Handle(ExportRequest{})
+	s.Nil(err)
+	s.Equal(int32(3), s.terminal.calls.Load())
+}
+
+// Test requests providing a key get a bucket per key
+func (s *RateLimitTestSuite) TestRateLimit_PerKey() {
+	// Given
+	p := s.newRateLimit(pipeline.Limit{Rate: 1, Burst: 1})
+	_, _ = p.Handle(SearchRequest{Tenant: "a"})
+
+	// When
+	_, errA := p.Handle(SearchRequest{Tenant: "a"})
+	_, errB := p.Handle(SearchRequest{Tenant: "b"})
+
+	// Then
+	s.EqualError(errA, `request "tests.SearchRequest" is rate limited for key "a", retry after 1s`)
+	s.Nil(errB)
+}
+
+// Test per request type limits override the default
+func (s *RateLimitTestSuite) TestRateLimit_PerRequestType() {
+	// Given
+	p := s.newRateLimit(pipeline.Limit{}, pipeline.RateLimitFor[ExportRequest](pipeline.Limit{Rate: 1, Burst: 1}))
+
+	// When
+	for i := 0; i < 3; i++ {
+		_, _ = p.Handle(SearchRequest{Tenant: "a"})
+		_, _ = p.Handle(ExportRequest{})
+	}
+
+	// Then
+	s.Equal(int32(4), s.terminal.calls.Load())
+}
+
+// Test wait mode waits until a token is available
+func (s *RateLimitTestSuite) TestRateLimit_Wait() {
+	// Given
+	p := s.newRateLimit(pipeline.Limit{Rate: 1, Burst: 1}, pipeline.WithRateLimitMode(pipeline.RateLimitWait))
+	_, _ = p.Handle(ExportRequest{})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.Handle(ExportRequest{})
+		done <- err
+	}()
+	s.Eventually(func() bool { return s.clock.Waiters() == 1 }, time.Second, time.Millisecond)
+
+	// When
+	s.clock.Advance(time.Second)
+
+	// Then
+	s.Nil(<-done)
+	s.Equal(int32(2), s.terminal.calls.Load())
+}
+
+// Test waiting stops when the request context is done
+func (s *RateLimitTestSuite) TestRateLimit_WaitCancelled() {
+	// Given
+	p := s.newRateLimit(pipeline.Limit{Rate: 1, Burst: 1}, pipeline.WithRateLimitMode(pipeline.RateLimitWait))
+	_, _ = p.Handle(ExportRequest{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// When
+	_, err := p.Handle(ExportRequest{}, ctx)
+
+	// Then
+	s.ErrorIs(err, context.Canceled)
+	s.Equal(int32(1), s.terminal.calls.Load())
+}
+
+// Test buckets that have refilled completely are removed
+func (s *RateLimitTestSuite) TestRateLimit_RemovesIdleBuckets() {
+	// Given
+	p := s.newRateLimit(pipeline.Limit{Rate: 1, Burst: 5})
+	for i := range 100 {
+		_, _ = p.Handle(SearchRequest{Tenant: fmt.Sprintf("tenant-%d", i)})
+	}
+	s.clock.Advance(time.Minute)
+	for range 5 {
+		_, _ = p.Handle(SearchRequest{Tenant: "busy"})
+	}
+	s.clock.Advance(time.Second)
+
+	// When
+	_, err := p.Handle(SearchRequest{Tenant: "new"})
+
+	// Then
+	s.Nil(err)
+	s.Equal(2, p.Buckets())
+	_, err = p.Handle(SearchRequest{Tenant: "busy"})
+	s.Nil(err)
+	_, err = p.Handle(SearchRequest{Tenant: "busy"})
+	s.ErrorIs(err, pipeline.ErrRateLimited)
+}
+
+// Test buckets are swept as soon as their number doubles
+func (s *RateLimitTestSuite) TestRateLimit_SweepsGrowingBuckets() {
+	// Given
+	p := s.newRateLimit(pipeline.Limit{Rate: 100, Burst: 1})
+
+	// When
+	for i := range 1000 {
+		_, _ = p.Handle(SearchRequest{Tenant: fmt.Sprintf("tenant-%d", i)})
+		s.clock.Advance(10 * time.Millisecond)
+	}
+
+	// Then
+	s.LessOrEqual(p.Buckets(), 128)
+}
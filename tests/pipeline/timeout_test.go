@@ -0,0 +1,144 @@
+// Test Suite for Timeout Pipeline
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/pipeline"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	SlowRequest          struct{}
+	ReportRequest        struct{}
+	CustomTimeoutRequest struct {
+		timeout time.Duration
+	}
+)
+
+func (r CustomTimeoutRequest) Timeout() time.Duration { return r.timeout }
+
+type TimeoutTestSuite struct {
+	suite.Suite
+	terminal *terminalPipeline
+}
+
+// Run Timeout Test Suite
+func TestTimeoutTestSuite(t *testing.T) {
+	suite.Run(t, new(TimeoutTestSuite))
+}
+
+func (s *TimeoutTestSuite) SetupTest() {
+	// The terminal waits for the context passed down the chain
+	s.terminal = &terminalPipeline{handle: func(request any, params ...any) (any, error) {
+		select {
+		case <-godiator.ContextFrom(params...).Done():
+			return nil, errors.New("cancelled")
+		case <-time.After(100 * time.Millisecond):
+			return "response", nil
+		}
+	}}
+}
+
+func (s *TimeoutTestSuite) newTimeout(defaultTimeout time.Duration, opts ...pipeline.TimeoutOption) *pipeline.Timeout {
+	p := pipeline.NewTimeout(defaultTimeout, opts...)
+	p.SetNext(s.terminal)
+	return p
+}
+
+// Test requests completing in time return their response
+func (s *TimeoutTestSuite) TestTimeout_CompletesInTime() {
+	// Given
+	p := s.newTimeout(time.Second)
+
+	// When
+	response, err := p.Handle(SlowRequest{})
+
+	// Then
+	s.Nil(err)
+	s.Equal("response", response)
+}
+
+// Test the default timeout returns a typed error
+func (s *TimeoutTestSuite) TestTimeout_DefaultTimeout() {
+	// Given
+	p := s.newTimeout(10 * time.Millisecond)
+
+	// When
+	response, err := p.Handle(SlowRequest{})
+
+	// Then
+	s.Nil(response)
+	s.ErrorIs(err, pipeline.ErrTimeout)
+	var timeoutErr *pipeline.TimeoutError
+	s.True(errors.As(err, &timeoutErr))
+	s.Equal("tests.SlowRequest", timeoutErr.RequestType)
+	s.Equal(10*time.Millisecond, timeoutErr.Timeout)
+}
+
+// Test per request type timeouts override the default
+func (s *TimeoutTestSuite) TestTimeout_PerRequestType() {
+	// Given
+	p := s.newTimeout(10*time.Millisecond, pipeline.TimeoutFor[ReportRequest](time.Second))
+
+	// When
+	_, slowErr := p.Handle(SlowRequest{})
+	_, reportErr := p.Handle(ReportRequest{})
+
+	// Then
+	s.ErrorIs(slowErr, pipeline.ErrTimeout)
+	s.Nil(reportErr)
+}
+
+// Test requests can choose their own timeout
+func (s *TimeoutTestSuite) TestTimeout_Timeouter() {
+	// Given
+	p := s.newTimeout(time.Second)
+
+	// When
+	_, err := p.Handle(CustomTimeoutRequest{timeout: 10 * time.Millisecond})
+
+	// Then
+	s.ErrorIs(err, pipeline.ErrTimeout)
+}
+
+// Test the context passed to the handler is cancelled on timeout
+func (s *TimeoutTestSuite) TestTimeout_CancelsContext() {
+	// Given
+	cancelled := make(chan struct{})
+	s.terminal.handle = func(request any, params ...any) (any, error) {
+		<-godiator.ContextFrom(params...).Done()
+		close(cancelled)
+		return nil, nil
+	}
+	p := s.newTimeout(10 * time.Millisecond)
+
+	// When
+	_, _ = p.Handle(SlowRequest{}, context.Background())
+
+	// Then
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		s.Fail("handler context was not cancelled")
+	}
+}
+
+// Test cancelling the caller's context returns the context error
+func (s *TimeoutTestSuite) TestTimeout_CallerCancelled() {
+	// Given
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p := s.newTimeout(time.Second)
+
+	// When
+	_, err := p.Handle(SlowRequest{}, ctx)
+
+	// Then
+	s.ErrorIs(err, context.Canceled)
+	s.NotErrorIs(err, pipeline.ErrTimeout)
+}
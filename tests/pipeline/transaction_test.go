@@ -0,0 +1,168 @@
+// Test Suite for Transaction Pipeline
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/pipeline"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeDriver records the statements and transaction outcomes of its connections
+type fakeDriver struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (d *fakeDriver) record(event string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.events = append(d.events, event)
+}
+
+func (d *fakeDriver) Events() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.events...)
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) { return &fakeConn{driver: d}, nil }
+
+type fakeConn struct{ driver *fakeDriver }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{driver: c.driver, query: query}, nil
+}
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.driver.record("begin")
+	return &fakeTx{driver: c.driver}, nil
+}
+
+type fakeTx struct{ driver *fakeDriver }
+
+func (t *fakeTx) Commit() error   { t.driver.record("commit"); return nil }
+func (t *fakeTx) Rollback() error { t.driver.record("rollback"); return nil }
+
+type fakeStmt struct {
+	driver *fakeDriver
+	query  string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.driver.record(s.query)
+	return driver.RowsAffected(1), nil
+}
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) { return nil, io.EOF }
+
+var transactionDriver = &fakeDriver{}
+
+func init() {
+	sql.Register("godiator-fake", transactionDriver)
+}
+
+type (
+	PlaceOrderCommand struct {
+		Fail  bool
+		Panic bool
+	}
+	OrderSummaryQuery struct{}
+)
+
+func (q OrderSummaryQuery) ReadOnly() bool { return true }
+
+type TransactionTestSuite struct {
+	suite.Suite
+	db          *sql.DB
+	transaction *pipeline.Transaction
+	terminal    *terminalPipeline
+}
+
+// Run Transaction Test Suite
+func TestTransactionTestSuite(t *testing.T) {
+	suite.Run(t, new(TransactionTestSuite))
+}
+
+func (s *TransactionTestSuite) SetupTest() {
+	transactionDriver.events = nil
+	db, err := sql.Open("godiator-fake", "")
+	s.Require().Nil(err)
+	s.db = db
+
+	s.terminal = &terminalPipeline{handle: func(request any, params ...any) (any, error) {
+		ctx := godiator.ContextFrom(params...)
+		tx, ok := pipeline.SQLTxFromContext(ctx)
+		if !ok {
+			return "no transaction", nil
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO orders"); err != nil {
+			return nil, err
+		}
+		command := request.(PlaceOrderCommand)
+		if command.Panic {
+			panic("handler panicked")
+		}
+		if command.Fail {
+			return nil, errors.New("out of stock")
+		}
+		return "placed", nil
+	}}
+	s.transaction = pipeline.NewTransaction(pipeline.NewSQLTxManager(db, nil))
+	s.transaction.SetNext(s.terminal)
+}
+
+func (s *TransactionTestSuite) TearDownTest() {
+	s.db.Close()
+}
+
+// Test successful commands are committed
+func (s *TransactionTestSuite) TestTransaction_Commit() {
+	// When
+	response, err := s.transaction.Handle(PlaceOrderCommand{}, context.Background())
+
+	// Then
+	s.Nil(err)
+	s.Equal("placed", response)
+	s.Equal([]string{"begin", "INSERT INTO orders", "commit"}, transactionDriver.Events())
+}
+
+// Test failed commands are rolled back
+func (s *TransactionTestSuite) TestTransaction_RollbackOnError() {
+	// When
+	_, err := s.transaction.Handle(PlaceOrderCommand{Fail: true})
+
+	// Then
+	s.EqualError(err, "out of stock")
+	s.Equal([]string{"begin", "INSERT INTO orders", "rollback"}, transactionDriver.Events())
+}
+
+// Test panicking commands are rolled back and the panic propagates
+func (s *TransactionTestSuite) TestTransaction_RollbackOnPanic() {
+	// When
+	s.PanicsWithValue("handler panicked", func() {
+		_, _ = s.transaction.Handle(PlaceOrderCommand{Panic: true})
+	})
+
+	// Then
+	s.Equal([]string{"begin", "INSERT INTO orders", "rollback"}, transactionDriver.Events())
+}
+
+// Test read-only requests run without a transaction
+func (s *TransactionTestSuite) TestTransaction_SkipsReadOnly() {
+	// When
+	response, err := s.transaction.Handle(OrderSummaryQuery{})
+
+	// Then
+	s.Nil(err)
+	s.Equal("no transaction", response)
+	s.Empty(transactionDriver.Events())
+}
@@ -0,0 +1,228 @@
+// Test Suite for Validation Pipeline
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/pipeline"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	Address struct {
+		City string `validate:"required"`
+	}
+	CreateUserRequest struct {
+		Name    string   `validate:"required,max=5"`
+		Age     int      `validate:"min=18,max=130"`
+		Email   string   `validate:"regex=^[^@,]+@[a-z]+(,|\\.)com$"`
+		Roles   []string `validate:"min=1"`
+		Address Address
+		Blocked bool
+	}
+	CreateUserResponse struct {
+		ID int
+	}
+	CategoryNode struct {
+		Name   string `validate:"required"`
+		Parent *CategoryNode
+		Alias  *CategoryNode
+	}
+	MisspelledRuleRequest struct {
+		Name string `validate:"requird"`
+	}
+	PointerBoundRequest struct {
+		Quantity *int `validate:"min=1"`
+	}
+	RenameUserRequest struct {
+		Name string
+	}
+	CreateUserHandler    struct{}
+	BlockedUserValidator struct{}
+)
+
+func (r *RenameUserRequest) Validate() error {
+	if r.Name == "" {
+		return pipeline.FieldError{Field: "Name", Message: "is required"}
+	}
+	return nil
+}
+
+func (r CreateUserRequest) Validate() error {
+	if r.Blocked {
+		return pipeline.FieldError{Field: "Blocked", Message: "must be false"}
+	}
+	return nil
+}
+
+func (v *BlockedUserValidator) Validate(request CreateUserRequest) error {
+	if request.Name == "admin" {
+		return errors.Join(
+			pipeline.FieldError{Field: "Name", Message: "is reserved"},
+			errors.New("admins cannot be created"),
+		)
+	}
+	return nil
+}
+
+func (h *CreateUserHandler) Handle(request CreateUserRequest, params ...any) (CreateUserResponse, error) {
+	return CreateUserResponse{ID: 1}, nil
+}
+
+type ValidationTestSuite struct {
+	suite.Suite
+	validation *pipeline.Validation
+	terminal   *terminalPipeline
+}
+
+// Run Validation Test Suite
+func TestValidationTestSuite(t *testing.T) {
+	suite.Run(t, new(ValidationTestSuite))
+}
+
+func (s *ValidationTestSuite) SetupTest() {
+	s.validation = pipeline.NewValidation()
+	s.terminal = &terminalPipeline{handle: func(request any, params ...any) (any, error) {
+		return "response", nil
+	}}
+	s.validation.SetNext(s.terminal)
+}
+
+func (s *ValidationTestSuite) TearDownTest() {
+	godiator.UnregisterValidators[CreateUserRequest]()
+}
+
+func validRequest() CreateUserRequest {
+	return CreateUserRequest{
+		Name:    "john",
+		Age:     30,
+		Email:   "john@example.com",
+		Roles:   []string{"user"},
+		Address: Address{City: "Istanbul"},
+	}
+}
+
+// Test valid requests reach the next pipeline
+func (s *ValidationTestSuite) TestValidation_Valid() {
+	// When
+	response, err := s.validation.Handle(validRequest())
+
+	// Then
+	s.Nil(err)
+	s.Equal("response", response)
+	s.Equal(int32(1), s.terminal.calls.Load())
+}
+
+// Test struct tag rules are aggregated into a validation error
+func (s *ValidationTestSuite) TestValidation_StructTags() {
+	// Given
+	request := CreateUserRequest{Name: "johnathan", Age: 12, Email: "invalid"}
+
+	// When
+	response, err := s.validation.Handle(request)
+
+	// Then
+	s.Nil(response)
+	s.Equal(int32(0), s.terminal.calls.Load())
+
+	var validationErr *pipeline.ValidationError
+	s.True(errors.As(err, &validationErr))
+	s.Equal("tests.CreateUserRequest", validationErr.RequestType)
+	s.Equal([]pipeline.FieldError{
+		{Field: "Name", Message: "length must be at most 5"},
+		{Field: "Age", Message: "must be at least 18"},
+		{Field: "Email", Message: `must match "^[^@,]+@[a-z]+(,|\\.)com$"`},
+		{Field: "Roles", Message: "length must be at least 1"},
+		{Field: "Address.City", Message: "is required"},
+	}, validationErr.Fields)
+}
+
+// Test Validatable requests and registered validators are evaluated
+func (s *ValidationTestSuite) TestValidation_ValidatableAndValidators() {
+	// Given
+	godiator.RegisterValidator[CreateUserRequest](&BlockedUserValidator{})
+	request := validRequest()
+	request.Name = "admin"
+	request.Blocked = true
+
+	// When
+	_, err := s.validation.Handle(request)
+
+	// Then
+	var validationErr *pipeline.ValidationError
+	s.True(errors.As(err, &validationErr))
+	s.Equal([]pipeline.FieldError{
+		{Field: "Blocked", Message: "must be false"},
+		{Field: "Name", Message: "is reserved"},
+		{Message: "admins cannot be created"},
+	}, validationErr.Fields)
+}
+
+// Test Validate implemented with a pointer receiver is evaluated for requests sent by value
+func (s *ValidationTestSuite) TestValidation_PointerReceiver() {
+	// When
+	_, err := s.validation.Handle(RenameUserRequest{})
+
+	// Then
+	var validationErr *pipeline.ValidationError
+	s.True(errors.As(err, &validationErr))
+	s.Equal([]pipeline.FieldError{{Field: "Name", Message: "is required"}}, validationErr.Fields)
+	s.Equal(int32(0), s.terminal.calls.Load())
+}
+
+// Test misconfigured tags are reported as configuration errors rather than field errors
+func (s *ValidationTestSuite) TestValidation_InvalidRules() {
+	// Given
+	quantity := 5
+
+	// When
+	_, misspelledErr := s.validation.Handle(MisspelledRuleRequest{Name: "john"})
+	_, pointerErr := s.validation.Handle(PointerBoundRequest{Quantity: &quantity})
+
+	// Then
+	var validationErr *pipeline.ValidationError
+	s.ErrorIs(misspelledErr, pipeline.ErrInvalidValidationRule)
+	s.False(errors.As(misspelledErr, &validationErr))
+	s.EqualError(misspelledErr, `validating "tests.MisspelledRuleRequest": invalid validation rule: field Name has unknown rule "requird"`)
+	s.ErrorIs(pointerErr, pipeline.ErrInvalidValidationRule)
+	s.EqualError(pointerErr, `validating "tests.PointerBoundRequest": invalid validation rule: field Quantity has min rule on a ptr field`)
+	s.Equal(int32(0), s.terminal.calls.Load())
+}
+
+// Test Send returns the validation error without invoking the handler
+func (s *ValidationTestSuite) TestValidation_Send() {
+	// Given
+	godiator.RegisterPipeline(pipeline.NewValidation())
+	godiator.RegisterHandler[CreateUserRequest, CreateUserResponse](&CreateUserHandler{})
+	defer core.ClearPipelines()
+
+	// When
+	response, err := godiator.Send[CreateUserRequest, CreateUserResponse](CreateUserRequest{})
+
+	// Then
+	s.Equal(CreateUserResponse{}, response)
+	s.ErrorContains(err, `validation failed for "tests.CreateUserRequest": Name is required`)
+}
+
+// Test self-referencing values are validated without following the cycle
+func (s *ValidationTestSuite) TestValidation_SelfReferencingValue() {
+	// Given
+	shared := &CategoryNode{}
+	root := &CategoryNode{Name: "root", Alias: shared}
+	root.Parent = root
+	shared.Parent = root
+
+	// When
+	fields, err := pipeline.ValidateStruct(CategoryNode{Parent: root, Alias: shared})
+
+	// Then
+	s.Nil(err)
+	s.Equal([]pipeline.FieldError{
+		{Field: "Name", Message: "is required"},
+		{Field: "Parent.Alias.Name", Message: "is required"},
+		{Field: "Alias.Name", Message: "is required"},
+	}, fields)
+}
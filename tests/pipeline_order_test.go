@@ -0,0 +1,171 @@
+// Test Suite for Pipeline Ordering
+package tests
+
+import (
+	"testing"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/mockiator"
+	"github.com/baranius/godiator/pipeline"
+	"github.com/stretchr/testify/suite"
+)
+
+type OrderRequest struct{}
+type OrderResponse struct{}
+
+// Records its label in the trace before calling the rest of the chain
+type LabelPipeline struct {
+	pipeline.BasePipeline
+	label string
+	trace *[]string
+}
+
+func (p *LabelPipeline) Handle(request any, params ...any) (any, error) {
+	*p.trace = append(*p.trace, p.label)
+	return p.Next().Handle(request, params...)
+}
+
+// Short-circuits the chain with a response of the wrong type
+type MismatchedResponsePipeline struct {
+	pipeline.BasePipeline
+}
+
+func (p *MismatchedResponsePipeline) Handle(request any, params ...any) (any, error) {
+	return "cached", nil
+}
+
+type PipelineOrderTestSuite struct {
+	suite.Suite
+	trace []string
+}
+
+// Run Pipeline Order Test Suite
+func TestPipelineOrderTestSuite(t *testing.T) {
+	suite.Run(t, new(PipelineOrderTestSuite))
+}
+
+func (s *PipelineOrderTestSuite) SetupTest() {
+	s.trace = nil
+	core.ClearPipelines()
+	mockiator.OnSend(func(request OrderRequest, params ...any) (OrderResponse, error) {
+		return OrderResponse{}, nil
+	})
+}
+
+func (s *PipelineOrderTestSuite) TearDownTest() {
+	core.ClearPipelines()
+}
+
+func (s *PipelineOrderTestSuite) label(label string) *LabelPipeline {
+	return &LabelPipeline{label: label, trace: &s.trace}
+}
+
+func (s *PipelineOrderTestSuite) send() {
+	_, err := godiator.Send[OrderRequest, OrderResponse](OrderRequest{})
+	s.Nil(err)
+}
+
+// Test pipelines without options run in registration order
+func (s *PipelineOrderTestSuite) TestPipelineOrder_RegistrationOrder() {
+	// Given
+	s.Nil(godiator.RegisterPipeline(s.label("first")))
+	s.Nil(godiator.RegisterPipeline(s.label("second")))
+
+	// When
+	s.send()
+
+	// Then
+	s.Equal([]string{"first", "second"}, s.trace)
+}
+
+// Test explicit orders take precedence over registration order
+func (s *PipelineOrderTestSuite) TestPipelineOrder_ExplicitOrder() {
+	// Given
+	s.Nil(godiator.RegisterPipeline(s.label("validation"), godiator.WithPipelineOrder(10)))
+	s.Nil(godiator.RegisterPipeline(s.label("default")))
+	s.Nil(godiator.RegisterPipeline(s.label("logging"), godiator.WithPipelineOrder(-10)))
+	s.Nil(godiator.RegisterPipeline(s.label("metrics"), godiator.WithPipelineOrder(-10)))
+
+	// When
+	s.send()
+
+	// Then
+	s.Equal([]string{"logging", "metrics", "default", "validation"}, s.trace)
+}
+
+// Test pipelines can be inserted relative to named pipelines
+func (s *PipelineOrderTestSuite) TestPipelineOrder_InsertBeforeAndAfter() {
+	// Given
+	s.Nil(godiator.RegisterPipeline(s.label("logging"), godiator.WithPipelineName("logging")))
+	s.Nil(godiator.RegisterPipeline(s.label("validation"), godiator.WithPipelineName("validation")))
+	s.Nil(godiator.RegisterPipeline(s.label("auth"), godiator.InsertBefore("validation")))
+	s.Nil(godiator.RegisterPipeline(s.label("tracing"), godiator.InsertAfter("logging")))
+
+	// When
+	s.send()
+
+	// Then
+	s.Equal([]string{"logging", "tracing", "auth", "validation"}, s.trace)
+}
+
+// Test named pipelines can be removed
+func (s *PipelineOrderTestSuite) TestPipelineOrder_Unregister() {
+	// Given
+	s.Nil(godiator.RegisterPipeline(s.label("logging"), godiator.WithPipelineName("logging")))
+	s.Nil(godiator.RegisterPipeline(s.label("validation")))
+
+	// When
+	removed := godiator.UnregisterPipeline("logging")
+	missing := godiator.UnregisterPipeline("logging")
+	s.send()
+
+	// Then
+	s.True(removed)
+	s.False(missing)
+	s.Equal([]string{"validation"}, s.trace)
+}
+
+// Test registration fails for duplicate names and unknown anchors
+func (s *PipelineOrderTestSuite) TestPipelineOrder_RegistrationErrors() {
+	// Given
+	s.Nil(godiator.RegisterPipeline(s.label("logging"), godiator.WithPipelineName("logging")))
+
+	// When
+	duplicateErr := godiator.RegisterPipeline(s.label("logging"), godiator.WithPipelineName("logging"))
+	missingErr := godiator.RegisterPipeline(s.label("auth"), godiator.InsertAfter("validation"))
+
+	// Then
+	s.EqualError(duplicateErr, `pipeline "logging" is already registered`)
+	s.EqualError(missingErr, `pipeline "validation" is not registered`)
+	s.Len(godiator.Pipelines(), 1)
+}
+
+// Test registered pipelines are listed in execution order
+func (s *PipelineOrderTestSuite) TestPipelineOrder_List() {
+	// Given
+	s.Nil(godiator.RegisterPipeline(s.label("default")))
+	s.Nil(godiator.RegisterTypedPipeline[ProfileRequest, ProfileResponse](&MaskEmailPipeline{trace: &s.trace}, godiator.WithPipelineName("mask"), godiator.WithPipelineOrder(-1)))
+
+	// When
+	pipelines := godiator.Pipelines()
+
+	// Then
+	s.Equal([]godiator.PipelineInfo{
+		{Name: "mask", Order: -1, Type: "*tests.MaskEmailPipeline"},
+		{Name: "", Order: 0, Type: "*tests.LabelPipeline"},
+	}, pipelines)
+}
+
+// Test a response that does not match the response type fails the send
+func (s *PipelineOrderTestSuite) TestPipelineOrder_MismatchedResponse() {
+	// Given
+	s.Nil(godiator.RegisterPipeline(&MismatchedResponsePipeline{}))
+
+	// When
+	response, err := godiator.Send[OrderRequest, OrderResponse](OrderRequest{})
+
+	// Then
+	s.EqualError(err, `response of type string returned for "tests.OrderRequest" is not a tests.OrderResponse`)
+	s.Equal(OrderResponse{}, response)
+}
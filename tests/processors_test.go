@@ -0,0 +1,133 @@
+// Test Suite for Processors and Exception Handlers
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/mockiator"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	SignUpRequest struct {
+		Email string
+	}
+	SignUpResponse struct {
+		Email string
+	}
+	NotFoundError struct {
+		Resource string
+	}
+	NormalizeEmailPreProcessor struct{}
+	RejectingPreProcessor      struct{}
+	AuditPostProcessor         struct {
+		audited []string
+	}
+	NotFoundExceptionHandler struct{}
+)
+
+func (e *NotFoundError) Error() string { return e.Resource + " not found" }
+
+func (p *NormalizeEmailPreProcessor) Process(request *SignUpRequest, params ...any) error {
+	request.Email = strings.ToLower(request.Email)
+	return nil
+}
+
+func (p *RejectingPreProcessor) Process(request *SignUpRequest, params ...any) error {
+	return errors.New("sign ups are closed")
+}
+
+func (p *AuditPostProcessor) Process(request SignUpRequest, response SignUpResponse, params ...any) error {
+	p.audited = append(p.audited, response.Email)
+	return nil
+}
+
+func (h *NotFoundExceptionHandler) Handle(request SignUpRequest, err *NotFoundError, params ...any) (SignUpResponse, bool) {
+	return SignUpResponse{Email: "fallback for " + err.Resource}, true
+}
+
+type ProcessorsTestSuite struct {
+	suite.Suite
+}
+
+// Run Processors Test Suite
+func TestProcessorsTestSuite(t *testing.T) {
+	suite.Run(t, new(ProcessorsTestSuite))
+}
+
+func (s *ProcessorsTestSuite) TearDownTest() {
+	godiator.UnregisterProcessors[SignUpRequest]()
+	godiator.UnregisterHandler[SignUpRequest]()
+}
+
+// Test pre-processors enrich the request and post-processors observe the response
+func (s *ProcessorsTestSuite) TestProcessors_PreAndPost() {
+	// Given
+	audit := &AuditPostProcessor{}
+	godiator.RegisterPreProcessor[SignUpRequest](&NormalizeEmailPreProcessor{})
+	godiator.RegisterPostProcessor[SignUpRequest, SignUpResponse](audit)
+	mockiator.OnSend(func(request SignUpRequest, params ...any) (SignUpResponse, error) {
+		return SignUpResponse{Email: request.Email}, nil
+	})
+
+	// When
+	response, err := godiator.Send[SignUpRequest, SignUpResponse](SignUpRequest{Email: "John@Example.com"})
+
+	// Then
+	s.Nil(err)
+	s.Equal("john@example.com", response.Email)
+	s.Equal([]string{"john@example.com"}, audit.audited)
+}
+
+// Test a failing pre-processor stops the request before the handler
+func (s *ProcessorsTestSuite) TestProcessors_PreProcessorError() {
+	// Given
+	audit := &AuditPostProcessor{}
+	godiator.RegisterPreProcessor[SignUpRequest](&RejectingPreProcessor{})
+	godiator.RegisterPostProcessor[SignUpRequest, SignUpResponse](audit)
+	handler := mockiator.OnSend(func(request SignUpRequest, params ...any) (SignUpResponse, error) {
+		return SignUpResponse{}, nil
+	})
+
+	// When
+	_, err := godiator.Send[SignUpRequest, SignUpResponse](SignUpRequest{})
+
+	// Then
+	s.EqualError(err, "sign ups are closed")
+	s.False(handler.IsCalled)
+	s.Empty(audit.audited)
+}
+
+// Test exception handlers turn matching errors into a fallback response
+func (s *ProcessorsTestSuite) TestExceptionHandler_MatchingError() {
+	// Given
+	godiator.RegisterExceptionHandler[SignUpRequest, SignUpResponse, *NotFoundError](&NotFoundExceptionHandler{})
+	mockiator.OnSend(func(request SignUpRequest, params ...any) (SignUpResponse, error) {
+		return SignUpResponse{}, &NotFoundError{Resource: "invitation"}
+	})
+
+	// When
+	response, err := godiator.Send[SignUpRequest, SignUpResponse](SignUpRequest{})
+
+	// Then
+	s.Nil(err)
+	s.Equal("fallback for invitation", response.Email)
+}
+
+// Test exception handlers leave other errors untouched
+func (s *ProcessorsTestSuite) TestExceptionHandler_OtherError() {
+	// Given
+	godiator.RegisterExceptionHandler[SignUpRequest, SignUpResponse, *NotFoundError](&NotFoundExceptionHandler{})
+	mockiator.OnSend(func(request SignUpRequest, params ...any) (SignUpResponse, error) {
+		return SignUpResponse{}, errors.New("database unavailable")
+	})
+
+	// When
+	_, err := godiator.Send[SignUpRequest, SignUpResponse](SignUpRequest{})
+
+	// Then
+	s.EqualError(err, "database unavailable")
+}
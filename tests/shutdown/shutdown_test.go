@@ -0,0 +1,220 @@
+// Test Suite for Graceful Shutdown. Shutdown affects the whole process, so it lives in its own package.
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/internal/testhooks"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	ExportRequest  struct{}
+	ExportResponse struct{}
+	ExportedEvent  struct{}
+	ProbeRequest   struct{}
+)
+
+// Handler taking some time once started, closed on shutdown
+type ExportHandler struct {
+	started chan struct{}
+	closed  atomic.Bool
+}
+
+func (h *ExportHandler) Handle(request ExportRequest, params ...any) (ExportResponse, error) {
+	close(h.started)
+	time.Sleep(20 * time.Millisecond)
+	return ExportResponse{}, nil
+}
+
+func (h *ExportHandler) Close() error {
+	h.closed.Store(true)
+	return nil
+}
+
+// Subscriber completing after a short delay
+type QuickSubscriber struct {
+	handled atomic.Int32
+}
+
+func (s *QuickSubscriber) Handle(request ExportedEvent, params ...any) {
+	time.Sleep(20 * time.Millisecond)
+	s.handled.Add(1)
+}
+
+// Subscriber blocking until released, failing to close
+type StuckSubscriber struct {
+	release chan struct{}
+}
+
+func (s *StuckSubscriber) Handle(request ExportedEvent, params ...any) {
+	<-s.release
+}
+
+func (s *StuckSubscriber) Close() error {
+	return errors.New("still running")
+}
+
+// Handler publishing an event with its context once released
+type PublishingExportHandler struct {
+	started    chan struct{}
+	release    chan struct{}
+	publishErr chan error
+}
+
+func (h *PublishingExportHandler) Handle(request ExportRequest, params ...any) (ExportResponse, error) {
+	close(h.started)
+	<-h.release
+	h.publishErr <- godiator.Publish(ExportedEvent{}, params...)
+	return ExportResponse{}, nil
+}
+
+// Batch handler counting the requests it processed
+type CountingExportBatchHandler struct {
+	processed atomic.Int32
+}
+
+func (h *CountingExportBatchHandler) Handle(requests []ExportRequest, params ...any) ([]ExportResponse, error) {
+	h.processed.Add(int32(len(requests)))
+	return make([]ExportResponse, len(requests)), nil
+}
+
+type ShutdownTestSuite struct {
+	suite.Suite
+}
+
+// Run Shutdown Test Suite
+func TestShutdownTestSuite(t *testing.T) {
+	suite.Run(t, new(ShutdownTestSuite))
+}
+
+func (s *ShutdownTestSuite) TearDownTest() {
+	godiator.UnregisterHandler[ExportRequest]()
+	godiator.UnregisterSubscriber[ExportedEvent]()
+	testhooks.ResetShutdown()
+}
+
+// Test shutdown drains in-flight work, reports abandoned work, rejects new work and closes closers
+func (s *ShutdownTestSuite) TestShutdown() {
+	// Given
+	handler := &ExportHandler{started: make(chan struct{})}
+	quick := &QuickSubscriber{}
+	stuck := &StuckSubscriber{release: make(chan struct{})}
+	defer close(stuck.release)
+	godiator.RegisterHandler[ExportRequest, ExportResponse](handler)
+	godiator.RegisterSubscriber[ExportedEvent](quick)
+	godiator.RegisterSubscriber[ExportedEvent](stuck)
+
+	sendErr := make(chan error, 1)
+	go func() {
+		_, err := godiator.Send[ExportRequest, ExportResponse](ExportRequest{})
+		sendErr <- err
+	}()
+	s.Nil(godiator.Publish(ExportedEvent{}))
+	<-handler.started
+
+	// When
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	err := godiator.Shutdown(ctx)
+
+	// Then
+	s.Nil(<-sendErr)
+	s.Equal(int32(1), quick.handled.Load())
+
+	var shutdownErr *godiator.ShutdownError
+	s.Require().True(errors.As(err, &shutdownErr))
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Require().Len(shutdownErr.Abandoned, 1)
+	s.Equal("subscriber", shutdownErr.Abandoned[0].Kind)
+	s.Equal("tests.ExportedEvent", shutdownErr.Abandoned[0].RequestType)
+	s.Equal("*tests.StuckSubscriber", shutdownErr.Abandoned[0].Subscriber)
+	s.ErrorContains(err, "closing *tests.StuckSubscriber: still running")
+	s.True(handler.closed.Load())
+
+	_, err = godiator.Send[ExportRequest, ExportResponse](ExportRequest{})
+	s.ErrorIs(err, godiator.ErrShuttingDown)
+	s.ErrorIs(godiator.Publish(ExportedEvent{}), godiator.ErrShuttingDown)
+}
+
+// Test handlers still running while shutting down can publish with their context, and the
+// subscribers are waited for
+func (s *ShutdownTestSuite) TestShutdown_NestedPublish() {
+	// Given
+	handler := &PublishingExportHandler{started: make(chan struct{}), release: make(chan struct{}), publishErr: make(chan error, 1)}
+	quick := &QuickSubscriber{}
+	godiator.RegisterHandler[ExportRequest, ExportResponse](handler)
+	godiator.RegisterSubscriber[ExportedEvent](quick)
+	go godiator.Send[ExportRequest, ExportResponse](ExportRequest{})
+	<-handler.started
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		shutdownErr <- godiator.Shutdown(ctx)
+	}()
+	s.Eventually(func() bool {
+		_, err := godiator.Send[ProbeRequest, ExportResponse](ProbeRequest{})
+		return errors.Is(err, godiator.ErrShuttingDown)
+	}, time.Second, time.Millisecond)
+
+	// When
+	close(handler.release)
+
+	// Then
+	s.Nil(<-handler.publishErr)
+	s.Nil(<-shutdownErr)
+	s.Equal(int32(1), quick.handled.Load())
+}
+
+// Test shutdown waits for a batch whose callers stopped waiting
+func (s *ShutdownTestSuite) TestShutdown_PendingBatch() {
+	// Given
+	handler := &CountingExportBatchHandler{}
+	godiator.RegisterBatchHandler[ExportRequest, ExportResponse](handler, godiator.WithBatchWindow(50*time.Millisecond))
+	sendCtx, cancelSend := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancelSend()
+	_, sendErr := godiator.Send[ExportRequest, ExportResponse](ExportRequest{}, sendCtx)
+
+	// When
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := godiator.Shutdown(ctx)
+
+	// Then
+	s.ErrorIs(sendErr, context.DeadlineExceeded)
+	s.Nil(err)
+	s.Equal(int32(1), handler.processed.Load())
+}
+
+// Test abandoned work is listed oldest first and work that completed is not
+func (s *ShutdownTestSuite) TestShutdown_AbandonedOrder() {
+	// Given
+	first := &StuckSubscriber{release: make(chan struct{})}
+	defer close(first.release)
+	godiator.RegisterSubscriber[ExportedEvent](first)
+	quick := &QuickSubscriber{}
+	godiator.RegisterSubscriber[ExportedEvent](quick)
+	s.Nil(godiator.Publish(ExportedEvent{}))
+	s.Nil(godiator.Publish(ExportedEvent{}))
+	s.Eventually(func() bool { return quick.handled.Load() == 2 }, time.Second, time.Millisecond)
+
+	// When
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := godiator.Shutdown(ctx)
+
+	// Then
+	var shutdownErr *godiator.ShutdownError
+	s.Require().True(errors.As(err, &shutdownErr))
+	s.Require().Len(shutdownErr.Abandoned, 2)
+	s.Equal("*tests.StuckSubscriber", shutdownErr.Abandoned[0].Subscriber)
+	s.Equal("*tests.StuckSubscriber", shutdownErr.Abandoned[1].Subscriber)
+	s.False(shutdownErr.Abandoned[0].Since.After(shutdownErr.Abandoned[1].Since))
+}
@@ -0,0 +1,142 @@
+// Test Suite for Singleflight Handlers
+package tests
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	ProductRequest struct {
+		SKU string
+	}
+	KeyedProductRequest struct {
+		SKU     string
+		TraceID int
+	}
+	ProductResponse struct {
+		SKU string
+	}
+	ProductHandler[TRequest any] struct {
+		executions atomic.Int32
+		release    chan struct{}
+		sku        func(TRequest) string
+	}
+)
+
+func (r KeyedProductRequest) SingleflightKey() string {
+	return r.SKU
+}
+
+func (h *ProductHandler[TRequest]) Handle(request TRequest, params ...any) (ProductResponse, error) {
+	h.executions.Add(1)
+	<-h.release
+	return ProductResponse{SKU: h.sku(request)}, nil
+}
+
+type SingleflightTestSuite struct {
+	suite.Suite
+}
+
+// Run Singleflight Test Suite
+func TestSingleflightTestSuite(t *testing.T) {
+	suite.Run(t, new(SingleflightTestSuite))
+}
+
+func sendAll[TRequest any](requests []TRequest, release chan struct{}) []ProductResponse {
+	var wg sync.WaitGroup
+	responses := make([]ProductResponse, len(requests))
+	for i, request := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i], _ = godiator.Send[TRequest, ProductResponse](request)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	return responses
+}
+
+// Test identical comparable requests share one execution
+func (s *SingleflightTestSuite) TestSingleflight_ComparableRequests() {
+	// Given
+	handler := &ProductHandler[ProductRequest]{
+		release: make(chan struct{}),
+		sku:     func(r ProductRequest) string { return r.SKU },
+	}
+	godiator.RegisterHandler[ProductRequest, ProductResponse](handler, godiator.WithSingleflight())
+	defer godiator.UnregisterHandler[ProductRequest]()
+
+	requests := []ProductRequest{{SKU: "a"}, {SKU: "a"}, {SKU: "a"}, {SKU: "b"}}
+
+	// When
+	responses := sendAll(requests, handler.release)
+
+	// Then
+	s.Equal(int32(2), handler.executions.Load())
+	for i, response := range responses {
+		s.Equal(requests[i].SKU, response.SKU)
+	}
+}
+
+// Test requests providing a key are deduplicated by that key
+func (s *SingleflightTestSuite) TestSingleflight_KeyedRequests() {
+	// Given
+	handler := &ProductHandler[KeyedProductRequest]{
+		release: make(chan struct{}),
+		sku:     func(r KeyedProductRequest) string { return r.SKU },
+	}
+	godiator.RegisterHandler[KeyedProductRequest, ProductResponse](handler, godiator.WithSingleflight())
+	defer godiator.UnregisterHandler[KeyedProductRequest]()
+
+	requests := []KeyedProductRequest{{SKU: "a", TraceID: 1}, {SKU: "a", TraceID: 2}, {SKU: "a", TraceID: 3}}
+
+	// When
+	responses := sendAll(requests, handler.release)
+
+	// Then
+	s.Equal(int32(1), handler.executions.Load())
+	for _, response := range responses {
+		s.Equal("a", response.SKU)
+	}
+}
+
+// Test callers stop waiting on their own context without failing the shared execution
+func (s *SingleflightTestSuite) TestSingleflight_CancelledCallers() {
+	// Given
+	handler := &ProductHandler[ProductRequest]{
+		release: make(chan struct{}),
+		sku:     func(r ProductRequest) string { return r.SKU },
+	}
+	godiator.RegisterHandler[ProductRequest, ProductResponse](handler, godiator.WithSingleflight())
+	defer godiator.UnregisterHandler[ProductRequest]()
+
+	starterCtx, cancelStarter := context.WithCancel(context.Background())
+	starterErr := make(chan error, 1)
+	go func() {
+		_, err := godiator.Send[ProductRequest, ProductResponse](ProductRequest{SKU: "a"}, starterCtx)
+		starterErr <- err
+	}()
+	s.Eventually(func() bool { return handler.executions.Load() == 1 }, time.Second, time.Millisecond)
+
+	// When
+	joinerCtx, cancelJoiner := context.WithCancel(context.Background())
+	cancelJoiner()
+	_, joinerErr := godiator.Send[ProductRequest, ProductResponse](ProductRequest{SKU: "a"}, joinerCtx)
+	cancelStarter()
+	responses := sendAll([]ProductRequest{{SKU: "a"}}, handler.release)
+
+	// Then
+	s.ErrorIs(joinerErr, context.Canceled)
+	s.ErrorIs(<-starterErr, context.Canceled)
+	s.Equal("a", responses[0].SKU)
+	s.Equal(int32(1), handler.executions.Load())
+}
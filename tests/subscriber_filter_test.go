@@ -0,0 +1,119 @@
+// Test Suite for Filtered Subscriptions
+package tests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/core"
+	"github.com/stretchr/testify/suite"
+)
+
+type PaymentProcessed struct {
+	PaymentID string
+	Status    string
+}
+
+type PaymentSubscriber struct {
+	mu       sync.Mutex
+	received []string
+}
+
+func (p *PaymentSubscriber) Handle(event PaymentProcessed, params ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.received = append(p.received, event.PaymentID)
+}
+
+func (p *PaymentSubscriber) Received() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.received...)
+}
+
+func failedPayments(event PaymentProcessed) bool {
+	return event.Status == "failed"
+}
+
+type SubscriberFilterTestSuite struct {
+	suite.Suite
+}
+
+// Run Subscriber Filter Test Suite
+func TestSubscriberFilterTestSuite(t *testing.T) {
+	suite.Run(t, new(SubscriberFilterTestSuite))
+}
+
+func (s *SubscriberFilterTestSuite) TearDownTest() {
+	godiator.UnregisterSubscriber[PaymentProcessed]()
+	godiator.DisableEventQueue()
+}
+
+// Test a filtered subscriber only receives the events matching its predicate
+func (s *SubscriberFilterTestSuite) TestFilter_MatchingEvents() {
+	// Given
+	alerts := &PaymentSubscriber{}
+	ledger := &PaymentSubscriber{}
+	godiator.RegisterSubscriber[PaymentProcessed](alerts, godiator.WithFilter(failedPayments))
+	godiator.RegisterSubscriber[PaymentProcessed](ledger)
+
+	// When
+	godiator.Publish(PaymentProcessed{PaymentID: "pay-1", Status: "succeeded"})
+	godiator.Publish(PaymentProcessed{PaymentID: "pay-2", Status: "failed"})
+
+	// Then
+	s.Eventually(func() bool { return len(ledger.Received()) == 2 && len(alerts.Received()) == 1 }, time.Second, time.Millisecond)
+	s.Equal([]string{"pay-2"}, alerts.Received())
+}
+
+// Test events filtered out by every subscriber are not scheduled
+func (s *SubscriberFilterTestSuite) TestFilter_NotScheduled() {
+	// Given
+	godiator.UseEventQueue(godiator.EventQueueConfig{Capacity: 4, Workers: 1})
+	alerts := &PaymentSubscriber{}
+	godiator.RegisterSubscriber[PaymentProcessed](alerts, godiator.WithFilter(failedPayments))
+
+	// When
+	err := godiator.Publish(PaymentProcessed{PaymentID: "pay-1", Status: "succeeded"})
+
+	// Then
+	s.NoError(err)
+	stats, ok := godiator.QueueStats()
+	s.True(ok)
+	s.Zero(stats.Enqueued)
+	s.Empty(alerts.Received())
+}
+
+// Test filtered subscribers keep the name of the subscriber they wrap
+func (s *SubscriberFilterTestSuite) TestFilter_SubscriberName() {
+	// Given
+	godiator.RegisterSubscriber[PaymentProcessed](&PaymentSubscriber{}, godiator.WithFilter(failedPayments))
+	godiator.RegisterSubscriber[PaymentProcessed](&PaymentSubscriber{},
+		godiator.WithFilter(failedPayments),
+		godiator.WithSubscriberName("alerts"))
+
+	// When
+	subscribers := core.GetSubscribers[PaymentProcessed]()
+
+	// Then
+	s.Require().Len(subscribers, 2)
+	s.Equal("*tests.PaymentSubscriber", subscribers[0].Name())
+	s.Equal("alerts", subscribers[1].Name())
+}
+
+// Test registering a filter for another event type panics
+func (s *SubscriberFilterTestSuite) TestFilter_MismatchedEventType() {
+	// Given
+	filter := godiator.WithFilter(func(event OrderCreated) bool { return true })
+
+	// When
+	register := func() {
+		godiator.RegisterSubscriber[PaymentProcessed](&PaymentSubscriber{}, filter)
+	}
+
+	// Then
+	s.Panics(register)
+	s.Empty(core.GetSubscribers[PaymentProcessed]())
+}
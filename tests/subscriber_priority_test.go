@@ -0,0 +1,148 @@
+// Test Suite for Subscriber Priorities
+package tests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/core"
+	"github.com/stretchr/testify/suite"
+)
+
+type PriceChanged struct {
+	ProductID string
+}
+
+type RecordingSubscriber struct {
+	label  string
+	delay  time.Duration
+	record func(label string)
+}
+
+func (r *RecordingSubscriber) Handle(event PriceChanged, params ...any) {
+	time.Sleep(r.delay)
+	r.record(r.label)
+}
+
+type SubscriberPriorityTestSuite struct {
+	suite.Suite
+	mu       sync.Mutex
+	received []string
+}
+
+// Run Subscriber Priority Test Suite
+func TestSubscriberPriorityTestSuite(t *testing.T) {
+	suite.Run(t, new(SubscriberPriorityTestSuite))
+}
+
+func (s *SubscriberPriorityTestSuite) SetupTest() {
+	s.received = nil
+	godiator.SetDispatchMode[PriceChanged](godiator.DispatchSequential)
+}
+
+func (s *SubscriberPriorityTestSuite) TearDownTest() {
+	godiator.UnregisterSubscriber[PriceChanged]()
+	godiator.SetDispatchMode[PriceChanged](godiator.DispatchConcurrent)
+	godiator.DisableEventQueue()
+}
+
+func (s *SubscriberPriorityTestSuite) record(label string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.received = append(s.received, label)
+}
+
+func (s *SubscriberPriorityTestSuite) recorded() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.received...)
+}
+
+func (s *SubscriberPriorityTestSuite) subscriber(label string, delay time.Duration) *RecordingSubscriber {
+	return &RecordingSubscriber{label: label, delay: delay, record: s.record}
+}
+
+// Test subscribers are kept in descending order of priority, then in order of registration
+func (s *SubscriberPriorityTestSuite) TestPriority_SubscriberOrder() {
+	// Given
+	godiator.RegisterSubscriber[PriceChanged](s.subscriber("default", 0))
+	godiator.RegisterSubscriber[PriceChanged](s.subscriber("high", 0), godiator.WithPriority(10))
+	godiator.RegisterSubscriber[PriceChanged](s.subscriber("low", 0), godiator.WithPriority(-5))
+	godiator.RegisterSubscriber[PriceChanged](s.subscriber("high again", 0), godiator.WithPriority(10))
+
+	// When
+	subscribers := core.GetSubscribers[PriceChanged]()
+
+	// Then
+	s.Require().Len(subscribers, 4)
+	priorities := make([]int, len(subscribers))
+	for i, subscriber := range subscribers {
+		priorities[i] = subscriber.Priority()
+	}
+	s.Equal([]int{10, 10, 0, -5}, priorities)
+}
+
+// Test sequential dispatch runs each subscriber after the previous one, by priority
+func (s *SubscriberPriorityTestSuite) TestPriority_SequentialDispatch() {
+	// Given
+	godiator.RegisterSubscriber[PriceChanged](s.subscriber("webhook", 0))
+	godiator.RegisterSubscriber[PriceChanged](s.subscriber("cache", 20*time.Millisecond), godiator.WithPriority(10))
+	godiator.RegisterSubscriber[PriceChanged](s.subscriber("audit", 0), godiator.WithPriority(-1))
+
+	// When
+	err := godiator.Publish(PriceChanged{ProductID: "sku-1"})
+
+	// Then
+	s.NoError(err)
+	s.Eventually(func() bool { return len(s.recorded()) == 3 }, time.Second, time.Millisecond)
+	s.Equal([]string{"cache", "webhook", "audit"}, s.recorded())
+}
+
+// Test sequential dispatch honors priorities of decorated subscribers
+func (s *SubscriberPriorityTestSuite) TestPriority_DecoratedSubscriber() {
+	// Given
+	godiator.RegisterSubscriber[PriceChanged](s.subscriber("webhook", 0))
+	godiator.RegisterSubscriber[PriceChanged](s.subscriber("cache", 10*time.Millisecond),
+		godiator.WithPriority(1),
+		godiator.WithRetry(godiator.RetryPolicy{MaxAttempts: 2}))
+
+	// When
+	godiator.Publish(PriceChanged{ProductID: "sku-1"})
+
+	// Then
+	s.Eventually(func() bool { return len(s.recorded()) == 2 }, time.Second, time.Millisecond)
+	s.Equal([]string{"cache", "webhook"}, s.recorded())
+}
+
+// Test sequential dispatch keeps the order on the event queue
+func (s *SubscriberPriorityTestSuite) TestPriority_SequentialDispatchOnEventQueue() {
+	// Given
+	godiator.UseEventQueue(godiator.EventQueueConfig{Capacity: 4, Workers: 4})
+	godiator.RegisterSubscriber[PriceChanged](s.subscriber("webhook", 0))
+	godiator.RegisterSubscriber[PriceChanged](s.subscriber("cache", 20*time.Millisecond), godiator.WithPriority(10))
+
+	// When
+	err := godiator.Publish(PriceChanged{ProductID: "sku-1"})
+
+	// Then
+	s.NoError(err)
+	s.Eventually(func() bool { return len(s.recorded()) == 2 }, time.Second, time.Millisecond)
+	s.Equal([]string{"cache", "webhook"}, s.recorded())
+}
+
+// Test concurrent dispatch does not wait for higher priority subscribers
+func (s *SubscriberPriorityTestSuite) TestPriority_ConcurrentDispatch() {
+	// Given
+	godiator.SetDispatchMode[PriceChanged](godiator.DispatchConcurrent)
+	godiator.RegisterSubscriber[PriceChanged](s.subscriber("webhook", 0))
+	godiator.RegisterSubscriber[PriceChanged](s.subscriber("cache", 50*time.Millisecond), godiator.WithPriority(10))
+
+	// When
+	godiator.Publish(PriceChanged{ProductID: "sku-1"})
+
+	// Then
+	s.Eventually(func() bool { return len(s.recorded()) == 2 }, time.Second, time.Millisecond)
+	s.Equal([]string{"webhook", "cache"}, s.recorded())
+}
@@ -0,0 +1,116 @@
+// Test Suite for Tracing
+package tests
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/mockiator"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	TracedRequest struct {
+		Fail bool
+	}
+	TracedResponse struct{}
+	TracedEvent    struct{}
+)
+
+type TracingTestSuite struct {
+	suite.Suite
+	recorder *mockiator.SpanRecorder
+}
+
+// Run Tracing Test Suite
+func TestTracingTestSuite(t *testing.T) {
+	suite.Run(t, new(TracingTestSuite))
+}
+
+func (s *TracingTestSuite) SetupTest() {
+	s.recorder = mockiator.NewSpanRecorder()
+	godiator.SetTracer(s.recorder)
+	core.ClearPipelines()
+	mockiator.OnSend(func(request TracedRequest, params ...any) (TracedResponse, error) {
+		if request.Fail {
+			return TracedResponse{}, errors.New("failed")
+		}
+		return TracedResponse{}, nil
+	})
+}
+
+func (s *TracingTestSuite) TearDownTest() {
+	godiator.SetTracer(nil)
+	core.ClearPipelines()
+	core.RemoveSubscriber[TracedEvent]()
+}
+
+// Test Send starts a span with child spans for every pipeline and the handler
+func (s *TracingTestSuite) TestTracing_Send() {
+	// Given
+	var trace []string
+	godiator.RegisterPipeline(&LabelPipeline{label: "outer", trace: &trace}, godiator.WithPipelineName("outer"))
+	godiator.RegisterPipeline(&LabelPipeline{label: "inner", trace: &trace})
+
+	// When
+	_, err := godiator.Send[TracedRequest, TracedResponse](TracedRequest{})
+
+	// Then
+	s.Nil(err)
+	spans := s.recorder.Spans()
+	s.Len(spans, 4)
+	s.Equal("send tests.TracedRequest", spans[0].Name)
+	s.Equal(map[string]string{"godiator.request_type": "tests.TracedRequest"}, spans[0].Attributes)
+	s.Equal(0, spans[0].ParentID)
+	s.Equal("pipeline *tests.LabelPipeline", spans[1].Name)
+	s.Equal("outer", spans[1].Attributes["godiator.pipeline.name"])
+	s.Equal(spans[0].ID, spans[1].ParentID)
+	s.Equal(spans[1].ID, spans[2].ParentID)
+	s.Equal("handle tests.TracedRequest", spans[3].Name)
+	s.Equal(spans[2].ID, spans[3].ParentID)
+	for _, span := range spans {
+		s.True(span.Ended)
+		s.Nil(span.Err)
+	}
+}
+
+// Test failed requests mark their spans as failed
+func (s *TracingTestSuite) TestTracing_SendError() {
+	// When
+	_, err := godiator.Send[TracedRequest, TracedResponse](TracedRequest{Fail: true})
+
+	// Then
+	s.EqualError(err, "failed")
+	spans := s.recorder.Spans()
+	s.Len(spans, 2)
+	s.EqualError(spans[0].Err, "failed")
+	s.EqualError(spans[1].Err, "failed")
+}
+
+// Test subscriber spans start new traces linked to the publish span
+func (s *TracingTestSuite) TestTracing_Publish() {
+	// Given
+	var wg sync.WaitGroup
+	wg.Add(1)
+	mockiator.OnPublish(func(request TracedEvent, params ...any) { wg.Done() })
+
+	// When
+	godiator.Publish(TracedEvent{})
+	wg.Wait()
+
+	// Then
+	s.Eventually(func() bool {
+		spans := s.recorder.Spans()
+		return len(spans) == 2 && spans[1].Ended
+	}, time.Second, time.Millisecond)
+	spans := s.recorder.Spans()
+	s.Equal("publish tests.TracedEvent", spans[0].Name)
+	s.Contains(spans[1].Name, "subscriber ")
+	s.Equal("tests.TracedEvent", spans[1].Attributes["godiator.event_type"])
+	s.Equal(0, spans[1].ParentID)
+	s.Equal([]int{spans[0].ID}, spans[1].LinkIDs)
+}
@@ -0,0 +1,141 @@
+// Test Suite for Typed Pipelines
+package tests
+
+import (
+	"testing"
+
+	"github.com/baranius/godiator"
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/mockiator"
+	"github.com/baranius/godiator/pipeline"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	Audited interface {
+		AuditAction() string
+	}
+	ProfileRequest struct {
+		ID int
+	}
+	ProfileResponse struct {
+		Email string
+	}
+	SettingsRequest  struct{}
+	SettingsResponse struct{}
+)
+
+func (r ProfileRequest) AuditAction() string { return "view profile" }
+
+// Records the order in which rings of the chain are executed
+type TracePipeline struct {
+	pipeline.BasePipeline
+	trace *[]string
+}
+
+func (p *TracePipeline) Handle(request any, params ...any) (any, error) {
+	*p.trace = append(*p.trace, "untyped")
+	return p.Next().Handle(request, params...)
+}
+
+type MaskEmailPipeline struct {
+	trace *[]string
+}
+
+func (p *MaskEmailPipeline) Handle(request ProfileRequest, next func(ProfileRequest, ...any) (ProfileResponse, error), params ...any) (ProfileResponse, error) {
+	*p.trace = append(*p.trace, "typed")
+	request.ID++
+	response, err := next(request, append(params, "masked")...)
+	response.Email = "***"
+	return response, err
+}
+
+type AuditPipeline struct {
+	trace *[]string
+}
+
+func (p *AuditPipeline) Handle(request Audited, next func(Audited, ...any) (any, error), params ...any) (any, error) {
+	*p.trace = append(*p.trace, request.AuditAction())
+	return next(request, params...)
+}
+
+// Declares a response type the handler does not return
+type MisdeclaredSettingsPipeline struct{}
+
+func (p *MisdeclaredSettingsPipeline) Handle(request SettingsRequest, next func(SettingsRequest, ...any) (ProfileResponse, error), params ...any) (ProfileResponse, error) {
+	return next(request, params...)
+}
+
+type TypedPipelineTestSuite struct {
+	suite.Suite
+	trace []string
+}
+
+// Run Typed Pipeline Test Suite
+func TestTypedPipelineTestSuite(t *testing.T) {
+	suite.Run(t, new(TypedPipelineTestSuite))
+}
+
+func (s *TypedPipelineTestSuite) SetupTest() {
+	s.trace = nil
+	core.ClearPipelines()
+	godiator.RegisterPipelineFor[Audited](&AuditPipeline{trace: &s.trace})
+	godiator.RegisterPipeline(&TracePipeline{trace: &s.trace})
+	godiator.RegisterTypedPipeline[ProfileRequest, ProfileResponse](&MaskEmailPipeline{trace: &s.trace})
+}
+
+func (s *TypedPipelineTestSuite) TearDownTest() {
+	core.ClearPipelines()
+}
+
+// Test typed pipelines run in registration order alongside untyped pipelines
+func (s *TypedPipelineTestSuite) TestTypedPipeline_MatchingRequest() {
+	// Given
+	var handledID int
+	var handledParams []any
+	mockiator.OnSend(func(request ProfileRequest, params ...any) (ProfileResponse, error) {
+		handledID = request.ID
+		handledParams = params
+		return ProfileResponse{Email: "john@example.com"}, nil
+	})
+
+	// When
+	response, err := godiator.Send[ProfileRequest, ProfileResponse](ProfileRequest{ID: 1})
+
+	// Then
+	s.Nil(err)
+	s.Equal("***", response.Email)
+	s.Equal(2, handledID)
+	s.Contains(handledParams, "masked")
+	s.Equal([]string{"view profile", "untyped", "typed"}, s.trace)
+}
+
+// Test a response of another type than the typed pipeline declares is reported as an error
+func (s *TypedPipelineTestSuite) TestTypedPipeline_ResponseTypeMismatch() {
+	// Given
+	s.Nil(godiator.RegisterTypedPipeline[SettingsRequest, ProfileResponse](&MisdeclaredSettingsPipeline{}))
+	mockiator.OnSend(func(request SettingsRequest, params ...any) (SettingsResponse, error) {
+		return SettingsResponse{}, nil
+	})
+
+	// When
+	_, err := godiator.Send[SettingsRequest, SettingsResponse](SettingsRequest{})
+
+	// Then
+	s.EqualError(err, `response of type tests.SettingsResponse returned for "tests.SettingsRequest" is not a tests.ProfileResponse`)
+}
+
+// Test typed pipelines are skipped for other requests
+func (s *TypedPipelineTestSuite) TestTypedPipeline_OtherRequest() {
+	// Given
+	mockiator.OnSend(func(request SettingsRequest, params ...any) (SettingsResponse, error) {
+		return SettingsResponse{}, nil
+	})
+
+	// When
+	_, err := godiator.Send[SettingsRequest, SettingsResponse](SettingsRequest{})
+
+	// Then
+	s.Nil(err)
+	s.Equal([]string{"untyped"}, s.trace)
+}
@@ -0,0 +1,103 @@
+package godiator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/baranius/godiator/core"
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+// SetTracer sets the tracer that Send and Publish start their spans with.
+// Passing nil disables tracing, which is the default.
+//
+// Parameters:
+//   - tracer: The tracer
+//
+// Example:
+//
+//	godiator.SetTracer(otel.NewTracer(otelapi.Tracer("godiator")))
+func SetTracer(tracer interfaces.Tracer) {
+	core.SetTracer(tracer)
+}
+
+// startSpan starts a span as a child of the context found in params, and returns params
+// carrying the context of the span.
+func startSpan(tracer interfaces.Tracer, name string, config interfaces.SpanConfig, params []any) (interfaces.Span, []any) {
+	ctx, span := tracer.Start(ContextFrom(params...), name, config)
+	return span, WithContext(ctx, params...)
+}
+
+// finishSpan ends a span, marking it as failed if err points to an error. It is deferred,
+// so that a panic marks the span as failed before it is propagated.
+func finishSpan(span interfaces.Span, err *error) {
+	if r := recover(); r != nil {
+		span.SetError(fmt.Errorf("panic: %v", r))
+		span.End()
+		panic(r)
+	}
+	if err != nil && *err != nil {
+		span.SetError(*err)
+	}
+	span.End()
+}
+
+// tracedPipeline starts a span around a ring of the pipeline chain. The chain is linked
+// through the wrapped pipeline, so each wrapper calls its pipeline which calls the next wrapper.
+type tracedPipeline struct {
+	tracer   interfaces.Tracer
+	name     string
+	config   interfaces.SpanConfig
+	pipeline interfaces.Pipeline
+}
+
+func (p *tracedPipeline) Next() interfaces.Pipeline {
+	return p.pipeline.Next()
+}
+
+func (p *tracedPipeline) SetNext(next interfaces.Pipeline) {
+	p.pipeline.SetNext(next)
+}
+
+func (p *tracedPipeline) Handle(request any, params ...any) (response any, err error) {
+	span, params := startSpan(p.tracer, p.name, p.config, params)
+	defer finishSpan(span, &err)
+	return p.pipeline.Handle(request, params...)
+}
+
+// tracedChain wraps the registered pipelines and the handler so that each of them runs in its own span.
+func tracedChain(tracer interfaces.Tracer, requestType string, handler interfaces.Pipeline) ([]interfaces.Pipeline, interfaces.Pipeline) {
+	entries := core.ListPipelines()
+	pipelines := make([]interfaces.Pipeline, len(entries))
+	for i, entry := range entries {
+		pipelineType := pipelineTypeName(entry.Pipeline)
+		attributes := map[string]string{"godiator.request_type": requestType, "godiator.pipeline": pipelineType}
+		if entry.Name != "" {
+			attributes["godiator.pipeline.name"] = entry.Name
+		}
+		pipelines[i] = &tracedPipeline{
+			tracer:   tracer,
+			name:     "pipeline " + pipelineType,
+			config:   interfaces.SpanConfig{Attributes: attributes},
+			pipeline: entry.Pipeline,
+		}
+	}
+	handler = &tracedPipeline{
+		tracer:   tracer,
+		name:     "handle " + requestType,
+		config:   interfaces.SpanConfig{Attributes: map[string]string{"godiator.request_type": requestType}},
+		pipeline: handler,
+	}
+	return pipelines, handler
+}
+
+// startSubscriberSpan starts the span of a subscriber as the root of a new trace, linked to
+// the span of the publisher found in params.
+func startSubscriberSpan(tracer interfaces.Tracer, eventType string, subscriber string, params []any) (interfaces.Span, []any) {
+	publishCtx := ContextFrom(params...)
+	return startSpan(tracer, "subscriber "+subscriber, interfaces.SpanConfig{
+		Attributes: map[string]string{"godiator.event_type": eventType, "godiator.subscriber": subscriber},
+		Links:      []context.Context{publishCtx},
+		Root:       true,
+	}, params)
+}
@@ -0,0 +1,80 @@
+package godiator
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/baranius/godiator/core/interfaces"
+)
+
+var (
+	_ interfaces.Pipeline = (*typedPipeline[any, any])(nil)
+	_ interfaces.Pipeline = (*typedPipelineFor[any])(nil)
+)
+
+// typedPipeline adapts a TypedPipeline to a ring of the untyped pipeline chain
+type typedPipeline[TRequest any, TResponse any] struct {
+	nextPipeline interfaces.Pipeline
+	pipeline     interfaces.TypedPipeline[TRequest, TResponse]
+}
+
+func (tp *typedPipeline[TRequest, TResponse]) Next() interfaces.Pipeline {
+	return tp.nextPipeline
+}
+
+func (tp *typedPipeline[TRequest, TResponse]) SetNext(nextPipeline interfaces.Pipeline) {
+	tp.nextPipeline = nextPipeline
+}
+
+func (tp *typedPipeline[TRequest, TResponse]) Handle(request any, params ...any) (any, error) {
+	next := tp.nextPipeline
+	typedRequest, ok := request.(TRequest)
+	if !ok {
+		return next.Handle(request, params...)
+	}
+
+	return tp.pipeline.Handle(typedRequest, func(request TRequest, params ...any) (TResponse, error) {
+		response, err := next.Handle(request, params...)
+		// Pipelines that reject a request may return a nil response, but any other response
+		// must match the response type, as in send
+		typedResponse, ok := response.(TResponse)
+		if !ok && response != nil && err == nil {
+			err = fmt.Errorf(`response of type %T returned for "%s" is not a %s`, response, reflect.TypeOf(request).String(), reflect.TypeFor[TResponse]())
+		}
+		return typedResponse, err
+	}, params...)
+}
+
+func (tp *typedPipeline[TRequest, TResponse]) pipelineType() string {
+	return fmt.Sprintf("%T", tp.pipeline)
+}
+
+// typedPipelineFor adapts a TypedPipelineFor to a ring of the untyped pipeline chain
+type typedPipelineFor[TInterface any] struct {
+	nextPipeline interfaces.Pipeline
+	pipeline     interfaces.TypedPipelineFor[TInterface]
+}
+
+func (tp *typedPipelineFor[TInterface]) Next() interfaces.Pipeline {
+	return tp.nextPipeline
+}
+
+func (tp *typedPipelineFor[TInterface]) SetNext(nextPipeline interfaces.Pipeline) {
+	tp.nextPipeline = nextPipeline
+}
+
+func (tp *typedPipelineFor[TInterface]) Handle(request any, params ...any) (any, error) {
+	next := tp.nextPipeline
+	typedRequest, ok := request.(TInterface)
+	if !ok {
+		return next.Handle(request, params...)
+	}
+
+	return tp.pipeline.Handle(typedRequest, func(request TInterface, params ...any) (any, error) {
+		return next.Handle(request, params...)
+	}, params...)
+}
+
+func (tp *typedPipelineFor[TInterface]) pipelineType() string {
+	return fmt.Sprintf("%T", tp.pipeline)
+}